@@ -0,0 +1,275 @@
+package podcasts
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// atomFeed represents an Atom 1.0 feed document with itunes extensions.
+type atomFeed struct {
+	XMLName        xml.Name      `xml:"feed"`
+	XMLNS          string        `xml:"xmlns,attr"`
+	ItunesXMLNS    string        `xml:"xmlns:itunes,attr"`
+	Lang           string        `xml:"xml:lang,attr,omitempty"`
+	ID             string        `xml:"id"`
+	Title          string        `xml:"title"`
+	Subtitle       string        `xml:"subtitle,omitempty"`
+	Updated        string        `xml:"updated"`
+	Links          []*atomLink   `xml:"link"`
+	Authors        []*atomPerson `xml:"author"`
+	Rights         string        `xml:"rights,omitempty"`
+	Generator      string        `xml:"generator,omitempty"`
+	Logo           string        `xml:"logo,omitempty"`
+	Author         string        `xml:"itunes:author,omitempty"`
+	Type           ShowType      `xml:"itunes:type,omitempty"`
+	Block          string        `xml:"itunes:block,omitempty"`
+	Explicit       string        `xml:"itunes:explicit,omitempty"`
+	Complete       string        `xml:"itunes:complete,omitempty"`
+	NewFeedURL     string        `xml:"itunes:new-feed-url,omitempty"`
+	ItunesSubtitle string        `xml:"itunes:subtitle,omitempty"`
+	Summary        *CDATAText    `xml:"itunes:summary,omitempty"`
+	Owner          *ItunesOwner
+	Image          *ItunesImage
+	Categories     []*ItunesCategory `xml:"itunes:category"`
+	Entries        []*atomEntry      `xml:"entry"`
+}
+
+// atomLink represents link of an Atom feed or entry.
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length string `xml:"length,attr,omitempty"`
+}
+
+// atomPerson represents author of an Atom feed or entry.
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+// atomText represents a text construct of an Atom feed or entry.
+type atomText struct {
+	Type  string `xml:"type,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+// atomEntry represents entry of an Atom feed with itunes extensions.
+type atomEntry struct {
+	ID              string        `xml:"id"`
+	Title           string        `xml:"title"`
+	Updated         string        `xml:"updated"`
+	Published       string        `xml:"published,omitempty"`
+	Links           []*atomLink   `xml:"link"`
+	Authors         []*atomPerson `xml:"author"`
+	Summary         *atomText     `xml:"summary,omitempty"`
+	Content         *atomText     `xml:"content,omitempty"`
+	ItunesTitle     string        `xml:"itunes:title,omitempty"`
+	Block           string        `xml:"itunes:block,omitempty"`
+	Duration        *Duration     `xml:"itunes:duration,omitempty"`
+	Explicit        string        `xml:"itunes:explicit,omitempty"`
+	ClosedCaptioned string        `xml:"itunes:isClosedCaptioned,omitempty"`
+	Order           int           `xml:"itunes:order,omitempty"`
+	Season          int           `xml:"itunes:season,omitempty"`
+	Episode         int           `xml:"itunes:episode,omitempty"`
+	EpisodeType     EpisodeType   `xml:"itunes:episodeType,omitempty"`
+	Subtitle        string        `xml:"itunes:subtitle,omitempty"`
+	Image           *ItunesImage
+}
+
+// Atom marshalls feed to Atom 1.0 XML string.
+func (f *Feed) Atom() (string, error) {
+	var buf bytes.Buffer
+	if err := f.WriteAtom(&buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// WriteAtom writes the feed as Atom 1.0 XML to the given writer.
+// Podcast specific tags are kept as itunes extension elements.
+func (f *Feed) WriteAtom(w io.Writer) error {
+	if _, err := w.Write([]byte(xml.Header)); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(newAtomFeed(f.Channel))
+}
+
+// newAtomFeed maps the channel to an Atom feed.
+func newAtomFeed(c *Channel) *atomFeed {
+	if c == nil {
+		c = &Channel{}
+	}
+	feed := &atomFeed{
+		XMLNS:          atomXMLNS,
+		ItunesXMLNS:    itunesXMLNS,
+		Lang:           c.Language,
+		Title:          c.Title,
+		Subtitle:       c.Description,
+		Rights:         c.Copyright,
+		Generator:      c.Generator,
+		Author:         c.Author,
+		Type:           c.Type,
+		Block:          c.Block,
+		Explicit:       c.Explicit,
+		Complete:       c.Complete,
+		NewFeedURL:     c.NewFeedURL,
+		ItunesSubtitle: c.Subtitle,
+		Summary:        c.Summary,
+		Owner:          c.Owner,
+		Image:          c.Image,
+		Categories:     c.Categories,
+		Entries:        make([]*atomEntry, 0, len(c.Items)),
+	}
+	feed.ID, feed.Links = atomFeedLinks(c)
+	feed.Authors = atomAuthors(c)
+	if c.Image != nil {
+		feed.Logo = c.Image.Href
+	}
+	feed.Updated = atomUpdated(c)
+
+	for _, item := range c.Items {
+		if item != nil {
+			feed.Entries = append(feed.Entries, newAtomEntry(feed, item))
+		}
+	}
+	return feed
+}
+
+// atomFeedLinks returns the id and links of the Atom feed of the channel. The
+// id is the self link, or else the link of the channel.
+func atomFeedLinks(c *Channel) (string, []*atomLink) {
+	var id string
+	var links []*atomLink
+	if c.Link != "" {
+		id = c.Link
+		links = append(links, &atomLink{Href: c.Link, Rel: "alternate"})
+	}
+	for _, link := range c.AtomLinks {
+		if link != nil && link.Rel == "self" {
+			id = link.Href
+			links = append(links, &atomLink{Href: link.Href, Rel: "self", Type: "application/atom+xml"})
+		}
+	}
+	if id == "" {
+		// Atom requires an absolute id, derived from the title when the channel has no link.
+		id = nameUUID(c.Title)
+	}
+	return id, links
+}
+
+// atomAuthors returns the authors of the Atom feed of the channel. Atom
+// requires an author, taken from the owner or the title when the channel has none.
+func atomAuthors(c *Channel) []*atomPerson {
+	switch {
+	case c.Author != "":
+		return []*atomPerson{{Name: c.Author}}
+	case c.Owner != nil && c.Owner.Name != "":
+		return []*atomPerson{{Name: c.Owner.Name}}
+	case c.Title != "":
+		return []*atomPerson{{Name: c.Title}}
+	}
+	return nil
+}
+
+// atomUpdated returns the last time the channel changed, formatted for Atom.
+func atomUpdated(c *Channel) string {
+	updated := c.LastBuildDate
+	if updated == nil {
+		updated = latestPubDate(c.Items)
+	}
+	if updated == nil {
+		updated = c.PubDate
+	}
+	if updated == nil {
+		updated = &PubDate{}
+	}
+	return updated.UTC().Format(time.RFC3339)
+}
+
+// newAtomEntry maps the item to an entry of given Atom feed.
+func newAtomEntry(feed *atomFeed, item *Item) *atomEntry {
+	entry := &atomEntry{
+		ID:              atomID(feed.ID, item),
+		Title:           item.Title,
+		Updated:         feed.Updated,
+		ItunesTitle:     item.ItunesTitle,
+		Block:           item.Block,
+		Duration:        item.Duration,
+		Explicit:        item.Explicit,
+		ClosedCaptioned: item.ClosedCaptioned,
+		Order:           item.Order,
+		Season:          item.Season,
+		Episode:         item.Episode,
+		EpisodeType:     item.EpisodeType,
+		Subtitle:        item.Subtitle,
+		Image:           item.Image,
+	}
+	if item.PubDate != nil {
+		entry.Published = item.PubDate.UTC().Format(time.RFC3339)
+		entry.Updated = entry.Published
+	}
+	if item.GUIDIsPermaLink != "false" && isWebURL(item.GUID) {
+		entry.Links = append(entry.Links, &atomLink{Href: item.GUID, Rel: "alternate"})
+	}
+	if item.Enclosure != nil {
+		entry.Links = append(entry.Links, &atomLink{
+			Href:   item.Enclosure.URL,
+			Rel:    "enclosure",
+			Type:   item.Enclosure.Type,
+			Length: item.Enclosure.Length,
+		})
+	}
+	if item.Author != "" {
+		entry.Authors = []*atomPerson{{Name: item.Author}}
+	}
+	if summary := cdataValue(item.Description); summary != "" {
+		entry.Summary = &atomText{Type: "html", Value: summary}
+	} else if summary := cdataValue(item.Summary); summary != "" {
+		entry.Summary = &atomText{Type: "html", Value: summary}
+	}
+	if content := cdataValue(item.ContentEncoded); content != "" {
+		entry.Content = &atomText{Type: "html", Value: content}
+	}
+	return entry
+}
+
+// atomID returns an IRI identifying the item, as Atom requires entry ids to be absolute.
+// Items without guid or enclosure are identified by their title.
+func atomID(feedID string, item *Item) string {
+	id := item.GUID
+	if id == "" && item.Enclosure != nil {
+		id = item.Enclosure.URL
+	}
+	if u, err := url.Parse(id); err == nil && u.IsAbs() {
+		return id
+	}
+	if id == "" {
+		id = item.Title
+	}
+	if isWebURL(feedID) {
+		return feedID + "#" + url.PathEscape(id)
+	}
+	return nameUUID(feedID, id)
+}
+
+// nameUUID returns a urn:uuid IRI derived from given names, a version 8 UUID
+// built from their SHA-256 hash, so that the same names always give the same id.
+func nameUUID(names ...string) string {
+	h := sha256.New()
+	for _, name := range names {
+		// Names are length prefixed so that they cannot run into each other.
+		h.Write([]byte(strconv.Itoa(len(name)) + ":" + name))
+	}
+	b := h.Sum(nil)[:16]
+	b[6] = b[6]&0x0f | 0x80
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
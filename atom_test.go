@@ -0,0 +1,217 @@
+package podcasts
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testAtomFeed decodes the parts of an Atom feed checked by the tests.
+type testAtomFeed struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang    string   `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Links   []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+	Author  string `xml:"author>name"`
+	Entries []struct {
+		ID        string `xml:"id"`
+		Title     string `xml:"title"`
+		Updated   string `xml:"updated"`
+		Published string `xml:"published"`
+		Links     []struct {
+			Href   string `xml:"href,attr"`
+			Rel    string `xml:"rel,attr"`
+			Type   string `xml:"type,attr"`
+			Length string `xml:"length,attr"`
+		} `xml:"link"`
+		Summary  string `xml:"summary"`
+		Content  string `xml:"content"`
+		Duration string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+		Episode  int    `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episode"`
+	} `xml:"entry"`
+}
+
+func TestWriteAtom(t *testing.T) {
+	podcast := &Podcast{
+		Title:       "Atom Podcast",
+		Description: "A podcast in Atom",
+		Link:        "https://example.com",
+		Language:    "en",
+	}
+	podcast.AddItem(&Item{
+		Title:          "Episode 1",
+		GUID:           "https://example.com/1",
+		PubDate:        NewPubDate(time.Date(2024, 1, 1, 12, 0, 0, 0, time.FixedZone("", 2*60*60))),
+		Duration:       NewDuration(time.Minute*30 + time.Second*5),
+		Description:    &CDATAText{Value: "Short description"},
+		ContentEncoded: &CDATAText{Value: "<p>Show notes & links</p>"},
+		Episode:        1,
+		Enclosure: &Enclosure{
+			URL:    "https://example.com/1.mp3",
+			Length: "14567890",
+			Type:   "audio/mpeg",
+		},
+	})
+	podcast.AddItem(&Item{Title: "Episode 2", GUID: "episode 2", GUIDIsPermaLink: "false"})
+
+	feed, err := podcast.Feed(
+		Author(testAuthor),
+		Explicit,
+		Image("https://example.com/artwork.jpg"),
+		SelfLink("https://example.com/feed.xml"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	data, err := feed.Atom()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, want := range []string{
+		`<feed xmlns="http://www.w3.org/2005/Atom" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd" xml:lang="en">`,
+		`<itunes:author>` + testAuthor + `</itunes:author>`,
+		`<itunes:explicit>yes</itunes:explicit>`,
+		`<itunes:image href="https://example.com/artwork.jpg"></itunes:image>`,
+		`<content type="html">&lt;p&gt;Show notes &amp; links&lt;/p&gt;</content>`,
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("expected %v to contain %v", data, want)
+		}
+	}
+
+	var got testAtomFeed
+	if err := xml.Unmarshal([]byte(data), &got); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got.ID != "https://example.com/feed.xml" || got.Title != podcast.Title || got.Lang != "en" {
+		t.Errorf("unexpected feed %+v", got)
+	}
+	if got.Updated != "2024-01-01T10:00:00Z" {
+		t.Errorf("expected %v got %v", "2024-01-01T10:00:00Z", got.Updated)
+	}
+	if got.Author != testAuthor {
+		t.Errorf("expected %v got %v", testAuthor, got.Author)
+	}
+	if len(got.Links) != 2 || got.Links[0].Rel != "alternate" || got.Links[1].Rel != "self" {
+		t.Errorf("unexpected links %+v", got.Links)
+	}
+	if len(got.Entries) != 2 {
+		t.Fatalf("expected 2 entries got %d", len(got.Entries))
+	}
+
+	first := got.Entries[0]
+	if first.ID != "https://example.com/1" || first.Published != "2024-01-01T10:00:00Z" || first.Updated != first.Published {
+		t.Errorf("unexpected entry %+v", first)
+	}
+	if first.Summary != "Short description" || first.Content != "<p>Show notes & links</p>" {
+		t.Errorf("unexpected entry content %+v", first)
+	}
+	if first.Duration != "30:05" || first.Episode != 1 {
+		t.Errorf("unexpected entry extensions %+v", first)
+	}
+	if len(first.Links) != 2 || first.Links[0].Rel != "alternate" {
+		t.Fatalf("unexpected links %+v", first.Links)
+	}
+	enclosure := first.Links[1]
+	if enclosure.Rel != "enclosure" || enclosure.Href != "https://example.com/1.mp3" || enclosure.Type != "audio/mpeg" || enclosure.Length != "14567890" {
+		t.Errorf("unexpected enclosure link %+v", enclosure)
+	}
+
+	second := got.Entries[1]
+	if second.ID != "https://example.com/feed.xml#episode%202" {
+		t.Errorf("expected %v got %v", "https://example.com/feed.xml#episode%202", second.ID)
+	}
+	if second.Published != "" || second.Updated != got.Updated || len(second.Links) != 0 {
+		t.Errorf("unexpected entry %+v", second)
+	}
+}
+
+func TestWriteAtomEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&Feed{}).WriteAtom(&buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	var got testAtomFeed
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got.Updated != "0001-01-01T00:00:00Z" {
+		t.Errorf("expected %v got %v", "0001-01-01T00:00:00Z", got.Updated)
+	}
+}
+
+func TestWriteAtomError(t *testing.T) {
+	if err := (&Feed{}).WriteAtom(&failingWriter{}); err == nil {
+		t.Error("expected error from failing writer")
+	}
+}
+
+func TestWriteAtomNilLink(t *testing.T) {
+	feed := &Feed{Channel: &Channel{AtomLinks: []*AtomLink{nil, {Href: "https://example.com/feed.xml", Rel: "self"}}}}
+	var buf bytes.Buffer
+	if err := feed.WriteAtom(&buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if want := `<id>https://example.com/feed.xml</id>`; !strings.Contains(buf.String(), want) {
+		t.Errorf("expected %v to contain %v", buf.String(), want)
+	}
+	if err := feed.SetOptions(SelfLink("https://example.com/rss.xml")); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	buf.Reset()
+	if err := feed.WriteJSONFeed(&buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if want := `"feed_url": "https://example.com/rss.xml"`; !strings.Contains(buf.String(), want) {
+		t.Errorf("expected %v to contain %v", buf.String(), want)
+	}
+}
+
+func TestWriteAtomWithoutLink(t *testing.T) {
+	feed := &Feed{Channel: &Channel{
+		Title: "No Link",
+		Owner: &ItunesOwner{Name: "Podcast Owner", Email: "owner@example.com"},
+		Items: []*Item{{Title: "Episode 1", GUID: "episode-1"}, {Title: "Episode 2"}},
+	}}
+	var buf bytes.Buffer
+	if err := feed.WriteAtom(&buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	var got testAtomFeed
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	ids := []string{got.ID}
+	for _, entry := range got.Entries {
+		ids = append(ids, entry.ID)
+	}
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if u, err := url.Parse(id); err != nil || u.Scheme != "urn" || !strings.HasPrefix(id, "urn:uuid:") || len(id) != len("urn:uuid:")+36 {
+			t.Errorf("expected urn:uuid id got %v", id)
+		}
+		if seen[id] {
+			t.Errorf("duplicate id %v", id)
+		}
+		seen[id] = true
+	}
+	if got.Author != "Podcast Owner" {
+		t.Errorf("expected %v got %v", "Podcast Owner", got.Author)
+	}
+
+	var again bytes.Buffer
+	if err := feed.WriteAtom(&again); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if again.String() != buf.String() {
+		t.Errorf("expected stable ids got %v", again.String())
+	}
+}
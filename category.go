@@ -0,0 +1,230 @@
+package podcasts
+
+import "errors"
+
+// ErrInvalidCategory represents a error returned for a category or subcategory unknown to Apple Podcasts.
+var ErrInvalidCategory = errors.New("podcasts: invalid category")
+
+// CategoryName represents the text of an Apple Podcasts category or subcategory.
+type CategoryName string
+
+// Categories of Apple Podcasts, each followed by its subcategories.
+const (
+	CategoryArts                  CategoryName = "Arts"
+	CategoryArtsBooks             CategoryName = "Books"
+	CategoryArtsDesign            CategoryName = "Design"
+	CategoryArtsFashionAndBeauty  CategoryName = "Fashion & Beauty"
+	CategoryArtsFood              CategoryName = "Food"
+	CategoryArtsPerformingArts    CategoryName = "Performing Arts"
+	CategoryArtsVisualArts        CategoryName = "Visual Arts"
+	CategoryBusiness              CategoryName = "Business"
+	CategoryBusinessCareers       CategoryName = "Careers"
+	CategoryBusinessEntrepreneur  CategoryName = "Entrepreneurship"
+	CategoryBusinessInvesting     CategoryName = "Investing"
+	CategoryBusinessManagement    CategoryName = "Management"
+	CategoryBusinessMarketing     CategoryName = "Marketing"
+	CategoryBusinessNonProfit     CategoryName = "Non-Profit"
+	CategoryComedy                CategoryName = "Comedy"
+	CategoryComedyInterviews      CategoryName = "Comedy Interviews"
+	CategoryComedyImprov          CategoryName = "Improv"
+	CategoryComedyStandUp         CategoryName = "Stand-Up"
+	CategoryEducation             CategoryName = "Education"
+	CategoryEducationCourses      CategoryName = "Courses"
+	CategoryEducationHowTo        CategoryName = "How To"
+	CategoryEducationLanguage     CategoryName = "Language Learning"
+	CategoryEducationSelfImprove  CategoryName = "Self-Improvement"
+	CategoryFiction               CategoryName = "Fiction"
+	CategoryFictionComedy         CategoryName = "Comedy Fiction"
+	CategoryFictionDrama          CategoryName = "Drama"
+	CategoryFictionScienceFiction CategoryName = "Science Fiction"
+	CategoryGovernment            CategoryName = "Government"
+	CategoryHistory               CategoryName = "History"
+	CategoryHealthAndFitness      CategoryName = "Health & Fitness"
+	CategoryHealthAlternative     CategoryName = "Alternative Health"
+	CategoryHealthFitness         CategoryName = "Fitness"
+	CategoryHealthMedicine        CategoryName = "Medicine"
+	CategoryHealthMentalHealth    CategoryName = "Mental Health"
+	CategoryHealthNutrition       CategoryName = "Nutrition"
+	CategoryHealthSexuality       CategoryName = "Sexuality"
+	CategoryKidsAndFamily         CategoryName = "Kids & Family"
+	CategoryKidsEducation         CategoryName = "Education for Kids"
+	CategoryKidsParenting         CategoryName = "Parenting"
+	CategoryKidsPetsAndAnimals    CategoryName = "Pets & Animals"
+	CategoryKidsStories           CategoryName = "Stories for Kids"
+	CategoryLeisure               CategoryName = "Leisure"
+	CategoryLeisureAnimation      CategoryName = "Animation & Manga"
+	CategoryLeisureAutomotive     CategoryName = "Automotive"
+	CategoryLeisureAviation       CategoryName = "Aviation"
+	CategoryLeisureCrafts         CategoryName = "Crafts"
+	CategoryLeisureGames          CategoryName = "Games"
+	CategoryLeisureHobbies        CategoryName = "Hobbies"
+	CategoryLeisureHomeAndGarden  CategoryName = "Home & Garden"
+	CategoryLeisureVideoGames     CategoryName = "Video Games"
+	CategoryMusic                 CategoryName = "Music"
+	CategoryMusicCommentary       CategoryName = "Music Commentary"
+	CategoryMusicHistory          CategoryName = "Music History"
+	CategoryMusicInterviews       CategoryName = "Music Interviews"
+	CategoryNews                  CategoryName = "News"
+	CategoryNewsBusiness          CategoryName = "Business News"
+	CategoryNewsDaily             CategoryName = "Daily News"
+	CategoryNewsEntertainment     CategoryName = "Entertainment News"
+	CategoryNewsCommentary        CategoryName = "News Commentary"
+	CategoryNewsPolitics          CategoryName = "Politics"
+	CategoryNewsSports            CategoryName = "Sports News"
+	CategoryNewsTech              CategoryName = "Tech News"
+	CategoryReligion              CategoryName = "Religion & Spirituality"
+	CategoryReligionBuddhism      CategoryName = "Buddhism"
+	CategoryReligionChristianity  CategoryName = "Christianity"
+	CategoryReligionHinduism      CategoryName = "Hinduism"
+	CategoryReligionIslam         CategoryName = "Islam"
+	CategoryReligionJudaism       CategoryName = "Judaism"
+	CategoryReligionReligion      CategoryName = "Religion"
+	CategoryReligionSpirituality  CategoryName = "Spirituality"
+	CategoryScience               CategoryName = "Science"
+	CategoryScienceAstronomy      CategoryName = "Astronomy"
+	CategoryScienceChemistry      CategoryName = "Chemistry"
+	CategoryScienceEarth          CategoryName = "Earth Sciences"
+	CategoryScienceLife           CategoryName = "Life Sciences"
+	CategoryScienceMathematics    CategoryName = "Mathematics"
+	CategoryScienceNatural        CategoryName = "Natural Sciences"
+	CategoryScienceNature         CategoryName = "Nature"
+	CategorySciencePhysics        CategoryName = "Physics"
+	CategoryScienceSocial         CategoryName = "Social Sciences"
+	CategorySocietyAndCulture     CategoryName = "Society & Culture"
+	CategorySocietyDocumentary    CategoryName = "Documentary"
+	CategorySocietyJournals       CategoryName = "Personal Journals"
+	CategorySocietyPhilosophy     CategoryName = "Philosophy"
+	CategorySocietyPlaces         CategoryName = "Places & Travel"
+	CategorySocietyRelationships  CategoryName = "Relationships"
+	CategorySports                CategoryName = "Sports"
+	CategorySportsBaseball        CategoryName = "Baseball"
+	CategorySportsBasketball      CategoryName = "Basketball"
+	CategorySportsCricket         CategoryName = "Cricket"
+	CategorySportsFantasy         CategoryName = "Fantasy Sports"
+	CategorySportsFootball        CategoryName = "Football"
+	CategorySportsGolf            CategoryName = "Golf"
+	CategorySportsHockey          CategoryName = "Hockey"
+	CategorySportsRugby           CategoryName = "Rugby"
+	CategorySportsRunning         CategoryName = "Running"
+	CategorySportsSoccer          CategoryName = "Soccer"
+	CategorySportsSwimming        CategoryName = "Swimming"
+	CategorySportsTennis          CategoryName = "Tennis"
+	CategorySportsVolleyball      CategoryName = "Volleyball"
+	CategorySportsWilderness      CategoryName = "Wilderness"
+	CategorySportsWrestling       CategoryName = "Wrestling"
+	CategoryTechnology            CategoryName = "Technology"
+	CategoryTrueCrime             CategoryName = "True Crime"
+	CategoryTVAndFilm             CategoryName = "TV & Film"
+	CategoryTVAfterShows          CategoryName = "After Shows"
+	CategoryTVFilmHistory         CategoryName = "Film History"
+	CategoryTVFilmInterviews      CategoryName = "Film Interviews"
+	CategoryTVFilmReviews         CategoryName = "Film Reviews"
+	CategoryTVReviews             CategoryName = "TV Reviews"
+)
+
+// appleCategories maps each Apple Podcasts category to its subcategories.
+var appleCategories = map[CategoryName][]CategoryName{
+	CategoryArts: {
+		CategoryArtsBooks, CategoryArtsDesign, CategoryArtsFashionAndBeauty,
+		CategoryArtsFood, CategoryArtsPerformingArts, CategoryArtsVisualArts,
+	},
+	CategoryBusiness: {
+		CategoryBusinessCareers, CategoryBusinessEntrepreneur, CategoryBusinessInvesting,
+		CategoryBusinessManagement, CategoryBusinessMarketing, CategoryBusinessNonProfit,
+	},
+	CategoryComedy: {
+		CategoryComedyInterviews, CategoryComedyImprov, CategoryComedyStandUp,
+	},
+	CategoryEducation: {
+		CategoryEducationCourses, CategoryEducationHowTo, CategoryEducationLanguage,
+		CategoryEducationSelfImprove,
+	},
+	CategoryFiction: {
+		CategoryFictionComedy, CategoryFictionDrama, CategoryFictionScienceFiction,
+	},
+	CategoryGovernment: nil,
+	CategoryHistory:    nil,
+	CategoryHealthAndFitness: {
+		CategoryHealthAlternative, CategoryHealthFitness, CategoryHealthMedicine,
+		CategoryHealthMentalHealth, CategoryHealthNutrition, CategoryHealthSexuality,
+	},
+	CategoryKidsAndFamily: {
+		CategoryKidsEducation, CategoryKidsParenting, CategoryKidsPetsAndAnimals,
+		CategoryKidsStories,
+	},
+	CategoryLeisure: {
+		CategoryLeisureAnimation, CategoryLeisureAutomotive, CategoryLeisureAviation,
+		CategoryLeisureCrafts, CategoryLeisureGames, CategoryLeisureHobbies,
+		CategoryLeisureHomeAndGarden, CategoryLeisureVideoGames,
+	},
+	CategoryMusic: {
+		CategoryMusicCommentary, CategoryMusicHistory, CategoryMusicInterviews,
+	},
+	CategoryNews: {
+		CategoryNewsBusiness, CategoryNewsDaily, CategoryNewsEntertainment,
+		CategoryNewsCommentary, CategoryNewsPolitics, CategoryNewsSports, CategoryNewsTech,
+	},
+	CategoryReligion: {
+		CategoryReligionBuddhism, CategoryReligionChristianity, CategoryReligionHinduism,
+		CategoryReligionIslam, CategoryReligionJudaism, CategoryReligionReligion,
+		CategoryReligionSpirituality,
+	},
+	CategoryScience: {
+		CategoryScienceAstronomy, CategoryScienceChemistry, CategoryScienceEarth,
+		CategoryScienceLife, CategoryScienceMathematics, CategoryScienceNatural,
+		CategoryScienceNature, CategorySciencePhysics, CategoryScienceSocial,
+	},
+	CategorySocietyAndCulture: {
+		CategorySocietyDocumentary, CategorySocietyJournals, CategorySocietyPhilosophy,
+		CategorySocietyPlaces, CategorySocietyRelationships,
+	},
+	CategorySports: {
+		CategorySportsBaseball, CategorySportsBasketball, CategorySportsCricket,
+		CategorySportsFantasy, CategorySportsFootball, CategorySportsGolf,
+		CategorySportsHockey, CategorySportsRugby, CategorySportsRunning,
+		CategorySportsSoccer, CategorySportsSwimming, CategorySportsTennis,
+		CategorySportsVolleyball, CategorySportsWilderness, CategorySportsWrestling,
+	},
+	CategoryTechnology: nil,
+	CategoryTrueCrime:  nil,
+	CategoryTVAndFilm: {
+		CategoryTVAfterShows, CategoryTVFilmHistory, CategoryTVFilmInterviews,
+		CategoryTVFilmReviews, CategoryTVReviews,
+	},
+}
+
+// IsCategory reports whether main is an Apple Podcasts category.
+func IsCategory(main CategoryName) bool {
+	_, ok := appleCategories[main]
+	return ok
+}
+
+// IsSubcategory reports whether sub is an Apple Podcasts subcategory of main.
+func IsSubcategory(main, sub CategoryName) bool {
+	for _, valid := range appleCategories[main] {
+		if sub == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// Category adds an itunes:category of given feed, with its subcategories.
+// Names must match the Apple Podcasts categories exactly, as listed by the
+// Category constants, and subcategories must belong to main.
+func Category(main CategoryName, subs ...CategoryName) func(f *Feed) error {
+	return func(f *Feed) error {
+		if !IsCategory(main) {
+			return ErrInvalidCategory
+		}
+		category := &ItunesCategory{Text: string(main)}
+		for _, sub := range subs {
+			if !IsSubcategory(main, sub) {
+				return ErrInvalidCategory
+			}
+			category.Categories = append(category.Categories, &ItunesCategory{Text: string(sub)})
+		}
+		f.Channel.Categories = append(f.Channel.Categories, category)
+		return nil
+	}
+}
@@ -0,0 +1,93 @@
+package podcasts
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCategory(t *testing.T) {
+	feed, err := (&Podcast{Title: "Categories"}).Feed(
+		Category(CategoryTechnology),
+		Category(CategorySocietyAndCulture, CategorySocietyDocumentary, CategorySocietyPlaces),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	data, err := feed.XML()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, want := range []string{
+		`<itunes:category text="Technology"></itunes:category>`,
+		`<itunes:category text="Society &amp; Culture">`,
+		`<itunes:category text="Places &amp; Travel"></itunes:category>`,
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("expected %v to contain %v", data, want)
+		}
+	}
+	parsed, err := Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	category := parsed.Channel.Categories[1]
+	if category.Text != "Society & Culture" || len(category.Categories) != 2 || category.Categories[1].Text != "Places & Travel" {
+		t.Errorf("unexpected category %+v", category)
+	}
+}
+
+func TestCategoryErrors(t *testing.T) {
+	cases := map[string]func(f *Feed) error{
+		"Unknown":         Category("Society and Culture"),
+		"CaseMismatch":    Category("technology"),
+		"Subcategory":     Category(CategorySocietyDocumentary),
+		"Mismatched":      Category(CategoryArts, CategorySportsGolf),
+		"NoSubcategories": Category(CategoryTechnology, CategoryNewsTech),
+	}
+	for name, option := range cases {
+		t.Run(name, func(t *testing.T) {
+			feed := &Feed{Channel: &Channel{}}
+			if err := option(feed); err != ErrInvalidCategory {
+				t.Errorf("expected %v got %v", ErrInvalidCategory, err)
+			}
+			if len(feed.Channel.Categories) != 0 {
+				t.Errorf("expected no category got %+v", feed.Channel.Categories)
+			}
+		})
+	}
+}
+
+func TestCategoryTaxonomy(t *testing.T) {
+	parents := make(map[CategoryName]CategoryName)
+	for main, subs := range appleCategories {
+		for _, sub := range subs {
+			if IsCategory(sub) {
+				t.Errorf("subcategory %q of %q is also a category", sub, main)
+			}
+			if other, ok := parents[sub]; ok {
+				t.Errorf("subcategory %q belongs to both %q and %q", sub, other, main)
+			}
+			parents[sub] = main
+		}
+	}
+	if len(appleCategories) != 19 {
+		t.Errorf("expected 19 categories got %d", len(appleCategories))
+	}
+}
+
+func TestValidateCategories(t *testing.T) {
+	feed := &Feed{Channel: &Channel{Categories: []*ItunesCategory{
+		{Text: "Tech"},
+		{Text: "Arts", Categories: []*ItunesCategory{{Text: "Golf"}, {Text: "Books"}}},
+	}}}
+	var fields []string
+	for _, problem := range feed.Validate() {
+		if strings.HasPrefix(problem.Field, "Channel.Categories") {
+			fields = append(fields, problem.Field)
+		}
+	}
+	want := "Channel.Categories[0].Text, Channel.Categories[1].Categories[0].Text"
+	if got := strings.Join(fields, ", "); got != want {
+		t.Errorf("expected %v got %v", want, got)
+	}
+}
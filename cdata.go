@@ -0,0 +1,144 @@
+package podcasts
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidCDATA represents a error returned for CDATA text with characters
+// not allowed in XML, when rejected by SanitizeCDATA.
+var ErrInvalidCDATA = errors.New("podcasts: invalid character in CDATA")
+
+// CDATAPolicy represents how characters not allowed in XML, such as control
+// characters, lone surrogates or invalid UTF-8, are handled in CDATA text.
+type CDATAPolicy int
+
+const (
+	// CDATAReplace replaces each invalid character with U+FFFD, as CDATAText
+	// always does when marshalled.
+	CDATAReplace CDATAPolicy = iota
+	// CDATAStrip removes invalid characters.
+	CDATAStrip
+	// CDATAReject fails with ErrInvalidCDATA.
+	CDATAReject
+)
+
+// cdata is marshalled as the CDATA section of the element.
+type cdata struct {
+	Value string `xml:",cdata"`
+}
+
+// MarshalXML marshalls text as CDATA. Characters not allowed in XML are
+// replaced with U+FFFD, and "]]>" is split across two CDATA sections, so that
+// any content produces well-formed XML.
+func (c CDATAText) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	value, _ := sanitizeCDATA(c.Value, CDATAReplace)
+	return encoder.EncodeElement(cdata{value}, start)
+}
+
+// SanitizeCDATA applies given policy to the CDATA text of the channel and of
+// its items, copying items before changing them.
+func SanitizeCDATA(policy CDATAPolicy) func(f *Feed) error {
+	return func(f *Feed) error {
+		summary, err := sanitizeCDATAText("Channel.Summary", f.Channel.Summary, policy)
+		if err != nil {
+			return err
+		}
+		f.Channel.Summary = summary
+
+		items := make([]*Item, len(f.Channel.Items))
+		for i, item := range f.Channel.Items {
+			items[i] = item
+			if item == nil {
+				continue
+			}
+			field := fmt.Sprintf("Channel.Items[%d]", i)
+			description, err := sanitizeCDATAText(field+".Description", item.Description, policy)
+			if err != nil {
+				return err
+			}
+			content, err := sanitizeCDATAText(field+".ContentEncoded", item.ContentEncoded, policy)
+			if err != nil {
+				return err
+			}
+			itemSummary, err := sanitizeCDATAText(field+".Summary", item.Summary, policy)
+			if err != nil {
+				return err
+			}
+			if description != item.Description || content != item.ContentEncoded || itemSummary != item.Summary {
+				copied := *item
+				copied.Description = description
+				copied.ContentEncoded = content
+				copied.Summary = itemSummary
+				items[i] = &copied
+			}
+		}
+		f.Channel.Items = items
+		return nil
+	}
+}
+
+// sanitizeCDATAText returns text with given policy applied, or text itself if
+// it has no invalid characters.
+func sanitizeCDATAText(field string, text *CDATAText, policy CDATAPolicy) (*CDATAText, error) {
+	if text == nil {
+		return nil, nil
+	}
+	value, err := sanitizeCDATA(text.Value, policy)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, field)
+	}
+	if value == text.Value {
+		return text, nil
+	}
+	return &CDATAText{Value: value}, nil
+}
+
+// sanitizeCDATA applies given policy to the characters of value not allowed in XML.
+func sanitizeCDATA(value string, policy CDATAPolicy) (string, error) {
+	i := invalidXMLIndex(value)
+	if i < 0 {
+		return value, nil
+	}
+	if policy == CDATAReject {
+		return "", ErrInvalidCDATA
+	}
+	var b strings.Builder
+	b.Grow(len(value))
+	b.WriteString(value[:i])
+	for i < len(value) {
+		// Invalid UTF-8, including lone surrogates, is decoded as utf8.RuneError of size 1.
+		r, size := utf8.DecodeRuneInString(value[i:])
+		if isXMLChar(r) && !(r == utf8.RuneError && size == 1) {
+			b.WriteString(value[i : i+size])
+		} else if policy == CDATAReplace {
+			b.WriteRune(utf8.RuneError)
+		}
+		i += size
+	}
+	return b.String(), nil
+}
+
+// invalidXMLIndex returns the index of the first character of value not
+// allowed in XML, or -1 if there is none. A valid U+FFFD is allowed.
+func invalidXMLIndex(value string) int {
+	for i := 0; i < len(value); {
+		r, size := utf8.DecodeRuneInString(value[i:])
+		if (r == utf8.RuneError && size == 1) || !isXMLChar(r) {
+			return i
+		}
+		i += size
+	}
+	return -1
+}
+
+// isXMLChar reports whether r is in the Char production of XML 1.0.
+func isXMLChar(r rune) bool {
+	return r == '\t' || r == '\n' || r == '\r' ||
+		r >= 0x20 && r <= 0xD7FF ||
+		r >= 0xE000 && r <= 0xFFFD ||
+		r >= 0x10000 && r <= 0x10FFFF
+}
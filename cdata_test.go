@@ -0,0 +1,89 @@
+package podcasts
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCDATAText(t *testing.T) {
+	item := &Item{
+		Title:       "Episode 1",
+		Description: &CDATAText{"<p>a]]>b</p>"},
+		Summary:     &CDATAText{"bell\x07 \xed\xa0\x80 \xff end�"},
+	}
+	p := &Podcast{Title: "CDATA"}
+	p.AddItem(item)
+	feed, err := p.Feed()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	data, err := feed.XML()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, want := range []string{
+		"<description><![CDATA[<p>a]]]]><![CDATA[>b</p>]]></description>",
+		"<itunes:summary><![CDATA[bell� ��� � end�]]></itunes:summary>",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("expected %v to contain %v", data, want)
+		}
+	}
+	parsed, err := Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := parsed.Channel.Items[0].Description.Value; got != item.Description.Value {
+		t.Errorf("expected %v got %v", item.Description.Value, got)
+	}
+}
+
+func TestSanitizeCDATA(t *testing.T) {
+	cases := map[CDATAPolicy]string{
+		CDATAReplace: "a�b���c�",
+		CDATAStrip:   "abc�",
+	}
+	for policy, want := range cases {
+		item := &Item{Title: "Episode 1", ContentEncoded: &CDATAText{"a\x00b\xed\xb0\x80c�"}, Summary: &CDATAText{"valid"}}
+		feed := &Feed{Channel: &Channel{Items: []*Item{item}}}
+		if err := SanitizeCDATA(policy)(feed); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		sanitized := feed.Channel.Items[0]
+		if got := sanitized.ContentEncoded.Value; got != want {
+			t.Errorf("expected %q got %q", want, got)
+		}
+		if sanitized == item || item.ContentEncoded.Value != "a\x00b\xed\xb0\x80c�" {
+			t.Errorf("expected item to be copied before sanitizing")
+		}
+		if sanitized.Summary != item.Summary {
+			t.Errorf("expected valid text to be kept")
+		}
+	}
+
+	feed := &Feed{Channel: &Channel{
+		Summary: &CDATAText{"valid"},
+		Items:   []*Item{nil, {Description: &CDATAText{"form\x0cfeed"}}},
+	}}
+	err := SanitizeCDATA(CDATAReject)(feed)
+	if !errors.Is(err, ErrInvalidCDATA) || !strings.HasSuffix(err.Error(), "Channel.Items[1].Description") {
+		t.Errorf("expected %v got %v", ErrInvalidCDATA, err)
+	}
+}
+
+func TestValidateCDATA(t *testing.T) {
+	feed := &Feed{Channel: &Channel{
+		Summary: &CDATAText{"ok"},
+		Items:   []*Item{{Summary: &CDATAText{"\x1b[1mbold"}}},
+	}}
+	var fields []string
+	for _, problem := range feed.Validate() {
+		if strings.HasSuffix(problem.Field, "Summary") {
+			fields = append(fields, problem.Field)
+		}
+	}
+	if got := strings.Join(fields, ", "); got != "Channel.Items[0].Summary" {
+		t.Errorf("expected %v got %v", "Channel.Items[0].Summary", got)
+	}
+}
@@ -0,0 +1,211 @@
+package podcasts
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	pscXMLNS = "http://podlove.org/simple-chapters"
+
+	// pscVersion is the version of Podlove Simple Chapters written.
+	pscVersion = "1.2"
+
+	// chaptersJSONVersion is the version of the Podcast Index JSON chapters format written.
+	chaptersJSONVersion = "1.2.0"
+
+	// ChaptersJSONType is the media type of Podcast Index JSON chapters, for use with Chapters.
+	ChaptersJSONType = "application/json+chapters"
+)
+
+var (
+	// ErrInvalidChapters represents a error returned for chapters out of order or past the end of the episode.
+	ErrInvalidChapters = errors.New("podcasts: invalid chapters")
+
+	// ErrInvalidChapterTime represents a error returned for a chapter start time that cannot be parsed.
+	ErrInvalidChapterTime = errors.New("podcasts: invalid chapter time")
+)
+
+// Chapter represents a chapter of given item.
+type Chapter struct {
+	// Start is the time the chapter starts at from the beginning of the episode.
+	Start time.Duration
+	Title string
+	// Image is the url of the chapter artwork, if any.
+	Image string
+	// URL is the url of a web page about the chapter, if any.
+	URL string
+	// Hidden leaves the chapter out of the table of contents, written as
+	// toc false in JSON chapters. It is not part of Podlove Simple Chapters.
+	Hidden bool
+}
+
+// MarshalXML marshalls chapter as psc:chapter, with start in normal play time.
+func (c Chapter) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	start.Attr = append(start.Attr,
+		xml.Attr{Name: xml.Name{Local: "start"}, Value: formatChapterTime(c.Start)},
+		xml.Attr{Name: xml.Name{Local: "title"}, Value: c.Title},
+	)
+	if c.URL != "" {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "href"}, Value: c.URL})
+	}
+	if c.Image != "" {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "image"}, Value: c.Image})
+	}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	return encoder.EncodeToken(xml.EndElement{Name: start.Name})
+}
+
+// UnmarshalXML unmarshalls psc:chapter.
+func (c *Chapter) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	var decoded struct {
+		Start string `xml:"start,attr"`
+		Title string `xml:"title,attr"`
+		Href  string `xml:"href,attr"`
+		Image string `xml:"image,attr"`
+	}
+	if err := decoder.DecodeElement(&decoded, &start); err != nil {
+		return err
+	}
+	chapterStart, err := parseChapterTime(decoded.Start)
+	if err != nil {
+		return err
+	}
+	*c = Chapter{Start: chapterStart, Title: decoded.Title, URL: decoded.Href, Image: decoded.Image}
+	return nil
+}
+
+// SimpleChapters represents psc:chapters of given item, the Podlove Simple
+// Chapters of the episode. The same chapters can be written as a Podcast
+// Index JSON chapters file with WriteJSON.
+type SimpleChapters struct {
+	XMLName  xml.Name   `xml:"psc:chapters"`
+	Version  string     `xml:"version,attr"`
+	Chapters []*Chapter `xml:"psc:chapter"`
+}
+
+// usesPSCNamespace reports whether any item of the channel has psc:chapters.
+func usesPSCNamespace(c *Channel) bool {
+	for _, item := range c.Items {
+		if item != nil && item.SimpleChapters != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ItemChapters sets the psc:chapters of given item. Chapters must be in
+// order. Whether they start within the duration of the item is left to
+// Feed.Validate, so that options can be given in any order.
+func ItemChapters(chapters ...*Chapter) func(i *Item) error {
+	return func(i *Item) error {
+		for n := range chapters {
+			if chapterProblem(chapters, n, nil) != "" {
+				return ErrInvalidChapters
+			}
+		}
+		i.SimpleChapters = &SimpleChapters{Version: pscVersion, Chapters: chapters}
+		return nil
+	}
+}
+
+// chaptersJSON represents a Podcast Index JSON chapters file.
+type chaptersJSON struct {
+	Version  string         `json:"version"`
+	Chapters []*chapterJSON `json:"chapters"`
+}
+
+type chapterJSON struct {
+	StartTime float64 `json:"startTime"`
+	Title     string  `json:"title,omitempty"`
+	Img       string  `json:"img,omitempty"`
+	URL       string  `json:"url,omitempty"`
+	TOC       *bool   `json:"toc,omitempty"`
+}
+
+// WriteJSON writes the chapters in the Podcast Index JSON chapters format,
+// to be published as the podcast:chapters file of the item.
+func (s *SimpleChapters) WriteJSON(w io.Writer) error {
+	file := &chaptersJSON{Version: chaptersJSONVersion, Chapters: make([]*chapterJSON, 0, len(s.Chapters))}
+	for _, c := range s.Chapters {
+		if c == nil {
+			continue
+		}
+		chapter := &chapterJSON{
+			StartTime: c.Start.Seconds(),
+			Title:     c.Title,
+			Img:       c.Image,
+			URL:       c.URL,
+		}
+		if c.Hidden {
+			toc := false
+			chapter.TOC = &toc
+		}
+		file.Chapters = append(file.Chapters, chapter)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(file)
+}
+
+// chapterProblem describes what is wrong with chapter n of chapters, or
+// returns an empty string.
+func chapterProblem(chapters []*Chapter, n int, duration *Duration) string {
+	c := chapters[n]
+	switch {
+	case c == nil:
+		return "chapter is required"
+	case c.Title == "":
+		return "title is required"
+	case c.Start < 0:
+		return fmt.Sprintf("start %s must be positive", formatChapterTime(c.Start))
+	case n > 0 && chapters[n-1] != nil && c.Start <= chapters[n-1].Start:
+		return fmt.Sprintf("start %s must be after the start of the previous chapter %s",
+			formatChapterTime(c.Start), formatChapterTime(chapters[n-1].Start))
+	case duration != nil && duration.Duration > 0 && c.Start >= duration.Duration:
+		return fmt.Sprintf("start %s must be within the duration %s", formatChapterTime(c.Start), formatChapterTime(duration.Duration))
+	}
+	return ""
+}
+
+// formatChapterTime formats d in the HH:MM:SS.mmm normal play time format.
+func formatChapterTime(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	ms := int64(d / time.Millisecond)
+	return fmt.Sprintf("%s%02d:%02d:%02d.%03d", sign, ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+// parseChapterTime parses normal play time, in HH:MM:SS, MM:SS or SS format
+// with optional milliseconds.
+func parseChapterTime(value string) (time.Duration, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) > 3 {
+		return 0, ErrInvalidChapterTime
+	}
+	var minutes int64
+	for i, part := range parts[:len(parts)-1] {
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || n < 0 || (i > 0 && n >= 60) {
+			return 0, ErrInvalidChapterTime
+		}
+		minutes = minutes*60 + n
+	}
+	last := parts[len(parts)-1]
+	seconds, err := strconv.ParseFloat(last, 64)
+	if err != nil || !(seconds >= 0 && seconds < 1e9) || (len(parts) > 1 && seconds >= 60) || strings.ContainsAny(last, "eE+-xXpP") {
+		return 0, ErrInvalidChapterTime
+	}
+	return time.Duration(minutes)*time.Minute + time.Duration(seconds*1000+0.5)*time.Millisecond, nil
+}
@@ -0,0 +1,182 @@
+package podcasts
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupChapters() []*Chapter {
+	return []*Chapter{
+		{Title: "Introduction"},
+		{Start: 90*time.Second + 500*time.Millisecond, Title: "News & Notes", URL: "https://example.com/news"},
+		{Start: time.Hour + 2*time.Minute, Title: "Sponsor", Image: "https://example.com/sponsor.jpg", Hidden: true},
+	}
+}
+
+func TestSimpleChapters(t *testing.T) {
+	item, err := NewItem("Episode 1", "https://example.com/1",
+		ItemDuration(90*time.Minute),
+		ItemChapters(setupChapters()...),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	p := &Podcast{Title: "Chapters"}
+	p.AddItem(item)
+	feed, err := p.Feed()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	data, err := feed.XML()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, want := range []string{
+		`xmlns:psc="http://podlove.org/simple-chapters"`,
+		`<psc:chapters version="1.2">`,
+		`<psc:chapter start="00:00:00.000" title="Introduction"></psc:chapter>`,
+		`<psc:chapter start="00:01:30.500" title="News &amp; Notes" href="https://example.com/news"></psc:chapter>`,
+		`<psc:chapter start="01:02:00.000" title="Sponsor" image="https://example.com/sponsor.jpg"></psc:chapter>`,
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("expected %v to contain %v", data, want)
+		}
+	}
+
+	parsed, err := Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	chapters := parsed.Channel.Items[0].SimpleChapters
+	if chapters == nil || len(chapters.Chapters) != 3 {
+		t.Fatalf("unexpected chapters %+v", chapters)
+	}
+	want := *setupChapters()[1]
+	if got := *chapters.Chapters[1]; got != want {
+		t.Errorf("expected %+v got %+v", want, got)
+	}
+
+	plain, _ := (&Podcast{Title: "No chapters"}).Feed()
+	if data, _ := plain.XML(); strings.Contains(data, "xmlns:psc") {
+		t.Errorf("expected %v not to declare psc namespace", data)
+	}
+}
+
+func TestChaptersJSON(t *testing.T) {
+	var buf bytes.Buffer
+	chapters := &SimpleChapters{Version: pscVersion, Chapters: setupChapters()}
+	if err := chapters.WriteJSON(&buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := `{
+  "version": "1.2.0",
+  "chapters": [
+    {
+      "startTime": 0,
+      "title": "Introduction"
+    },
+    {
+      "startTime": 90.5,
+      "title": "News & Notes",
+      "url": "https://example.com/news"
+    },
+    {
+      "startTime": 3720,
+      "title": "Sponsor",
+      "img": "https://example.com/sponsor.jpg",
+      "toc": false
+    }
+  ]
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("expected %v got %v", want, got)
+	}
+}
+
+func TestItemChaptersErrors(t *testing.T) {
+	cases := map[string][]*Chapter{
+		"Unordered": {{Start: time.Minute, Title: "Second"}, {Title: "First"}},
+		"SameStart": {{Title: "First"}, {Title: "Second"}},
+		"Negative":  {{Start: -time.Second, Title: "First"}},
+		"NoTitle":   {{}},
+		"Nil":       {nil},
+	}
+	for name, chapters := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewItem("Episode", "guid", ItemDuration(time.Hour), ItemChapters(chapters...)); err != ErrInvalidChapters {
+				t.Errorf("expected %v got %v", ErrInvalidChapters, err)
+			}
+		})
+	}
+}
+
+func TestItemChaptersOptionOrder(t *testing.T) {
+	chapters := []*Chapter{{Title: "First"}, {Start: time.Hour, Title: "Past the end"}}
+	for _, options := range [][]func(i *Item) error{
+		{ItemDuration(time.Minute), ItemChapters(chapters...)},
+		{ItemChapters(chapters...), ItemDuration(time.Minute)},
+	} {
+		item, err := NewItem("Episode", "guid", options...)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		feed := &Feed{Channel: &Channel{Items: []*Item{item}}}
+		if !hasProblem(feed.Validate(), "Channel.Items[0].SimpleChapters.Chapters[1]", SeverityError) {
+			t.Errorf("expected error for chapter past the duration in %v", feed.Validate())
+		}
+	}
+}
+
+func TestValidateChapters(t *testing.T) {
+	item := &Item{
+		Duration: NewDuration(10 * time.Minute),
+		SimpleChapters: &SimpleChapters{Chapters: []*Chapter{
+			{Title: "Introduction", Image: "sponsor.jpg"},
+			{Start: 5 * time.Minute, Title: "Middle"},
+			{Start: 4 * time.Minute, Title: "Out of order"},
+			{Start: 20 * time.Minute, Title: "Past the end"},
+		}},
+	}
+	feed := &Feed{Channel: &Channel{Items: []*Item{item}}}
+	var fields []string
+	for _, problem := range feed.Validate() {
+		if strings.Contains(problem.Field, "SimpleChapters") {
+			fields = append(fields, problem.Field)
+		}
+	}
+	want := "Channel.Items[0].SimpleChapters.Chapters[0].Image, Channel.Items[0].SimpleChapters.Chapters[2], Channel.Items[0].SimpleChapters.Chapters[3]"
+	if got := strings.Join(fields, ", "); got != want {
+		t.Errorf("expected %v got %v", want, got)
+	}
+}
+
+func TestChapterTime(t *testing.T) {
+	cases := map[string]time.Duration{
+		"00:00:00.000": 0,
+		"01:02:03.456": time.Hour + 2*time.Minute + 3*time.Second + 456*time.Millisecond,
+		"1:02:03":      time.Hour + 2*time.Minute + 3*time.Second,
+		"02:03.5":      2*time.Minute + 3*time.Second + 500*time.Millisecond,
+		"125":          125 * time.Second,
+		"100:00:00":    100 * time.Hour,
+	}
+	for value, want := range cases {
+		got, err := parseChapterTime(value)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", value, err)
+		}
+		if got != want {
+			t.Errorf("%q: expected %v got %v", value, want, got)
+		}
+	}
+	for _, value := range []string{"", "1:2:3:4", "00:60", "1:60:00", "-1", "1e3", "NaN", "a:00"} {
+		if _, err := parseChapterTime(value); err != ErrInvalidChapterTime {
+			t.Errorf("%q: expected %v got %v", value, ErrInvalidChapterTime, err)
+		}
+	}
+	if got := formatChapterTime(time.Hour + 2*time.Minute + 3*time.Second + 456*time.Millisecond); got != "01:02:03.456" {
+		t.Errorf("expected %v got %v", "01:02:03.456", got)
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/CallumKerson/podcasts/cmd/podcasts
+
+go 1.17
+
+require (
+	github.com/CallumKerson/podcasts v0.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+replace github.com/CallumKerson/podcasts => ../..
@@ -0,0 +1,135 @@
+// Command podcasts generates a podcast feed from a show definition file.
+//
+// The show definition is written in YAML or JSON and lists the channel
+// metadata, the feed options and the episodes:
+//
+//	title: My podcast
+//	description: This is my very simple podcast.
+//	link: https://www.example-podcast.com/my-podcast
+//	language: en
+//	author: Author Name
+//	explicit: false
+//	owner:
+//	  name: Podcast Owner
+//	  email: owner@example-podcast.com
+//	image: https://www.example-podcast.com/my-podcast.jpg
+//	categories:
+//	  - text: Technology
+//	episodes:
+//	  - title: Episode 1
+//	    guid: https://www.example-podcast.com/my-podcast/1/episode-one
+//	    pub_date: 2024-01-01T12:00:00Z
+//	    duration: "5:20"
+//	    enclosure:
+//	      url: https://www.example-podcast.com/my-podcast/1/episode.mp3
+//	      length: 12312
+//	      type: audio/mpeg
+//
+// Usage:
+//
+//	podcasts [-o output] [-format rss|atom|json] [-validate] show.yaml
+//
+// The feed is written to standard output unless -o is given. The definition
+// is read from standard input when the file is "-".
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/CallumKerson/podcasts"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command with the given arguments and returns the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("podcasts", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	output := flags.String("o", "", "write the feed to `file` instead of standard output")
+	format := flags.String("format", "rss", "output `format`: rss, atom or json")
+	validate := flags.Bool("validate", false, "fail when the feed does not meet Apple Podcasts requirements")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: podcasts [flags] show.yaml")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	if err := generate(flags.Arg(0), *output, *format, *validate, stdin, stdout, stderr); err != nil {
+		fmt.Fprintf(stderr, "podcasts: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// generate reads the show definition at input and writes its feed to output.
+func generate(input, output, format string, validate bool, stdin io.Reader, stdout, stderr io.Writer) error {
+	write, err := writer(format)
+	if err != nil {
+		return err
+	}
+
+	r := stdin
+	if input != "-" {
+		file, err := os.Open(input)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+	s, err := readShow(r)
+	if err != nil {
+		return fmt.Errorf("%s: %w", input, err)
+	}
+	feed, err := s.feed()
+	if err != nil {
+		return fmt.Errorf("%s: %w", input, err)
+	}
+
+	if validate {
+		problems := feed.Validate()
+		for _, problem := range problems.Warnings() {
+			fmt.Fprintln(stderr, problem)
+		}
+		if err := problems.Err(); err != nil {
+			return err
+		}
+	}
+
+	if output == "" {
+		return write(feed, stdout)
+	}
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := write(feed, file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writer returns the function writing feeds in the given format.
+func writer(format string) (func(f *podcasts.Feed, w io.Writer) error, error) {
+	switch format {
+	case "rss":
+		return (*podcasts.Feed).Write, nil
+	case "atom":
+		return (*podcasts.Feed).WriteAtom, nil
+	case "json":
+		return (*podcasts.Feed).WriteJSONFeed, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CallumKerson/podcasts"
+)
+
+func TestRunYAML(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"testdata/show.yaml"}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0 got %d: %s", code, stderr.String())
+	}
+	feed, err := podcasts.Parse(&stdout)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	c := feed.Channel
+	if c.Title != "My podcast" || c.Author != "Author Name" || c.Type != podcasts.Serial || c.Explicit != podcasts.ValueYes {
+		t.Errorf("unexpected channel %+v", c)
+	}
+	if c.TTL != 60 || len(c.SkipDays.Days) != 2 || c.SkipDays.Days[0] != "Saturday" {
+		t.Errorf("unexpected channel %+v", c)
+	}
+	if len(c.AtomLinks) != 1 || c.AtomLinks[0].Href != "https://www.example-podcast.com/feed.xml" {
+		t.Errorf("unexpected atom links %+v", c.AtomLinks)
+	}
+	if len(c.Categories) != 2 || c.Categories[1].Categories[0].Text != "Documentary" {
+		t.Errorf("unexpected categories %+v", c.Categories)
+	}
+	if len(c.Items) != 2 {
+		t.Fatalf("expected 2 items got %d", len(c.Items))
+	}
+	first, second := c.Items[0], c.Items[1]
+	if first.Duration.Duration != time.Second*320 || first.Enclosure.Length != "12312" || first.Description.Value != "The first episode." {
+		t.Errorf("unexpected item %+v", first)
+	}
+	if second.GUIDIsPermaLink != "false" || !second.PubDate.Equal(time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected item %+v", second)
+	}
+	if !strings.Contains(second.ContentEncoded.Value, "<b>show notes</b>") || !strings.HasSuffix(second.Summary.Value, "- Markdown\n- HTML") {
+		t.Errorf("unexpected item %+v", second)
+	}
+	if problems := feed.Validate(); problems.Err() != nil {
+		t.Errorf("unexpected validation errors %v", problems)
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "feed.json")
+	var stderr bytes.Buffer
+	args := []string{"-o", output, "-format", "json", "-validate", "testdata/show.json"}
+	if code := run(args, nil, nil, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0 got %d: %s", code, stderr.String())
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	var got struct {
+		Title  string `json:"title"`
+		Itunes struct {
+			Explicit string `json:"explicit"`
+		} `json:"_itunes"`
+		Items []struct {
+			ID string `json:"id"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got.Title != "My podcast" || got.Itunes.Explicit != "false" || len(got.Items) != 1 {
+		t.Errorf("unexpected feed %+v", got)
+	}
+	if !strings.Contains(stderr.String(), "warning: Channel.AtomLinks") {
+		t.Errorf("expected validation warning got %v", stderr.String())
+	}
+}
+
+func TestRunStdin(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	stdin := strings.NewReader("title: Standard input\nepisodes:\n  - title: Episode 1\n")
+	if code := run([]string{"-format", "atom", "-"}, stdin, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0 got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "<title>Standard input</title>") || !strings.Contains(stdout.String(), "<entry>") {
+		t.Errorf("unexpected output %v", stdout.String())
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	cases := map[string]struct {
+		args  []string
+		stdin string
+		code  int
+	}{
+		"NoArguments":     {nil, "", 2},
+		"UnknownFlag":     {[]string{"-unknown", "show.yaml"}, "", 2},
+		"MissingFile":     {[]string{"testdata/missing.yaml"}, "", 1},
+		"UnknownFormat":   {[]string{"-format", "html", "testdata/show.yaml"}, "", 1},
+		"Empty":           {[]string{"-"}, "", 1},
+		"UnknownKey":      {[]string{"-"}, "titel: Typo\n", 1},
+		"InvalidOption":   {[]string{"-"}, "type: weekly\n", 1},
+		"InvalidImage":    {[]string{"-"}, "image: artwork.jpg\n", 1},
+		"InvalidDay":      {[]string{"-"}, "skip_days: [someday]\n", 1},
+		"TTLWithoutUnit":  {[]string{"-"}, "ttl: 60\n", 1},
+		"TTLInSeconds":    {[]string{"-"}, "ttl: 90s\n", 1},
+		"JSONTTL":         {[]string{"-"}, "{\"ttl\": 60}", 1},
+		"InvalidPubDate":  {[]string{"-"}, "episodes:\n  - pub_date: yesterday\n", 1},
+		"InvalidDuration": {[]string{"-"}, "episodes:\n  - duration: long\n", 1},
+		"InvalidFeed":     {[]string{"-validate", "-"}, "title: Invalid\n", 1},
+		"DuplicateGUID":   {[]string{"-"}, "episodes:\n  - guid: a\n  - guid: a\n", 1},
+		"InvalidCategory": {[]string{"-"}, "categories:\n  - text: Society & Culture\n    subcategories: [Documentaries]\n", 1},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(c.args, strings.NewReader(c.stdin), &stdout, &stderr); code != c.code {
+				t.Errorf("expected exit code %d got %d", c.code, code)
+			}
+			if stderr.Len() == 0 {
+				t.Error("expected error output")
+			}
+		})
+	}
+}
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CallumKerson/podcasts"
+	"gopkg.in/yaml.v3"
+)
+
+// show represents a show definition file, listing the channel metadata,
+// the feed options and the episodes.
+type show struct {
+	Title          string      `yaml:"title"`
+	Description    string      `yaml:"description"`
+	Link           string      `yaml:"link"`
+	Language       string      `yaml:"language"`
+	Copyright      string      `yaml:"copyright"`
+	Author         string      `yaml:"author"`
+	Type           string      `yaml:"type"`
+	Block          bool        `yaml:"block"`
+	Explicit       *bool       `yaml:"explicit"`
+	Complete       bool        `yaml:"complete"`
+	NewFeedURL     string      `yaml:"new_feed_url"`
+	Subtitle       string      `yaml:"subtitle"`
+	Summary        string      `yaml:"summary"`
+	Owner          *owner      `yaml:"owner"`
+	Image          string      `yaml:"image"`
+	Locked         string      `yaml:"locked"`
+	Funding        []*funding  `yaml:"funding"`
+	SelfLink       string      `yaml:"self_link"`
+	TTL            string      `yaml:"ttl"`
+	Generator      string      `yaml:"generator"`
+	ManagingEditor string      `yaml:"managing_editor"`
+	WebMaster      string      `yaml:"web_master"`
+	Docs           string      `yaml:"docs"`
+	Published      string      `yaml:"published"`
+	LastBuildDate  string      `yaml:"last_build_date"`
+	SkipHours      []int       `yaml:"skip_hours"`
+	SkipDays       []string    `yaml:"skip_days"`
+	Categories     []*category `yaml:"categories"`
+	Episodes       []*episode  `yaml:"episodes"`
+}
+
+// owner represents the itunes:owner of a show.
+type owner struct {
+	Name  string `yaml:"name"`
+	Email string `yaml:"email"`
+}
+
+// funding represents a podcast:funding link of a show.
+type funding struct {
+	URL  string `yaml:"url"`
+	Text string `yaml:"text"`
+}
+
+// category represents an itunes:category of a show with its subcategories.
+type category struct {
+	Text          string   `yaml:"text"`
+	Subcategories []string `yaml:"subcategories"`
+}
+
+// episode represents an item of a show.
+type episode struct {
+	Title           string     `yaml:"title"`
+	ItunesTitle     string     `yaml:"itunes_title"`
+	GUID            string     `yaml:"guid"`
+	PermaLink       *bool      `yaml:"perma_link"`
+	PubDate         string     `yaml:"pub_date"`
+	Description     string     `yaml:"description"`
+	Content         string     `yaml:"content"`
+	Notes           string     `yaml:"notes"`
+	Author          string     `yaml:"author"`
+	Block           bool       `yaml:"block"`
+	Explicit        *bool      `yaml:"explicit"`
+	ClosedCaptioned bool       `yaml:"closed_captioned"`
+	Duration        string     `yaml:"duration"`
+	Season          int        `yaml:"season"`
+	Episode         int        `yaml:"episode"`
+	EpisodeType     string     `yaml:"episode_type"`
+	Subtitle        string     `yaml:"subtitle"`
+	Summary         string     `yaml:"summary"`
+	Image           string     `yaml:"image"`
+	Enclosure       *enclosure `yaml:"enclosure"`
+}
+
+// enclosure represents the media file of an episode.
+type enclosure struct {
+	URL    string `yaml:"url"`
+	Length int64  `yaml:"length"`
+	Type   string `yaml:"type"`
+}
+
+// readShow decodes a show definition in YAML, or in JSON as it is a subset of YAML.
+// Unknown keys are rejected so that typos do not silently drop options.
+func readShow(r io.Reader) (*show, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	s := &show{}
+	if err := dec.Decode(s); err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("empty show definition")
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// feed builds the feed of the show.
+func (s *show) feed() (*podcasts.Feed, error) {
+	p := &podcasts.Podcast{
+		Title:       s.Title,
+		Description: s.Description,
+		Link:        s.Link,
+		Language:    s.Language,
+		Copyright:   s.Copyright,
+		Duplicates:  podcasts.DuplicateError,
+	}
+	for i, e := range s.Episodes {
+		item, err := e.item()
+		if err != nil {
+			return nil, fmt.Errorf("episodes[%d]: %w", i, err)
+		}
+		if err := p.TryAddItem(item); err != nil {
+			return nil, fmt.Errorf("episodes[%d]: %w", i, err)
+		}
+	}
+	options, err := s.options()
+	if err != nil {
+		return nil, err
+	}
+	return p.Feed(options...)
+}
+
+// feedOption is an option of the feed of a show.
+type feedOption = func(f *podcasts.Feed) error
+
+// itemOption is an option of the item of an episode.
+type itemOption = func(i *podcasts.Item) error
+
+// feedSetting builds the feed option of a show setting, if it is set.
+type feedSetting struct {
+	key   string
+	set   bool
+	build func() (feedOption, error)
+}
+
+// itemSetting builds the item option of an episode setting, if it is set.
+type itemSetting struct {
+	key   string
+	set   bool
+	build func() (itemOption, error)
+}
+
+// options maps the show settings to the options of the feed.
+func (s *show) options() ([]feedOption, error) {
+	settings := []feedSetting{
+		{"author", s.Author != "", feedFixed(podcasts.Author(s.Author))},
+		{"type", s.Type != "", feedFixed(podcasts.Type(podcasts.ShowType(s.Type)))},
+		{"block", s.Block, feedFixed(podcasts.Block)},
+		{"explicit", s.Explicit != nil, s.explicit},
+		{"complete", s.Complete, feedFixed(podcasts.Complete)},
+		{"new_feed_url", s.NewFeedURL != "", feedFixed(podcasts.NewFeedURL(s.NewFeedURL))},
+		{"subtitle", s.Subtitle != "", feedFixed(podcasts.Subtitle(s.Subtitle))},
+		{"summary", s.Summary != "", feedFixed(podcasts.Summary(s.Summary))},
+		{"owner", s.Owner != nil, s.owner},
+		{"image", s.Image != "", feedFixed(podcasts.Image(s.Image))},
+		{"locked", s.Locked != "", feedFixed(podcasts.Locked(s.Locked))},
+		{"self_link", s.SelfLink != "", feedFixed(podcasts.SelfLink(s.SelfLink))},
+		{"ttl", s.TTL != "", s.ttl},
+		{"generator", s.Generator != "", feedFixed(podcasts.Generator(s.Generator))},
+		{"managing_editor", s.ManagingEditor != "", feedFixed(podcasts.ManagingEditor(s.ManagingEditor))},
+		{"web_master", s.WebMaster != "", feedFixed(podcasts.WebMaster(s.WebMaster))},
+		{"docs", s.Docs != "", feedFixed(podcasts.Docs(s.Docs))},
+		{"published", s.Published != "", dateOption(s.Published, podcasts.Published)},
+		{"last_build_date", s.LastBuildDate != "", dateOption(s.LastBuildDate, podcasts.LastBuildDate)},
+		{"skip_hours", len(s.SkipHours) > 0, feedFixed(podcasts.SkipHours(s.SkipHours...))},
+		{"skip_days", len(s.SkipDays) > 0, s.skipDays},
+	}
+	var options []feedOption
+	for _, setting := range settings {
+		if !setting.set {
+			continue
+		}
+		option, err := setting.build()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", setting.key, err)
+		}
+		options = append(options, option)
+	}
+	for i, c := range s.Categories {
+		options = append(options, c.option(i))
+	}
+	for _, f := range s.Funding {
+		options = append(options, podcasts.Funding(f.URL, f.Text))
+	}
+	return options, nil
+}
+
+// feedFixed returns a builder of the given feed option.
+func feedFixed(option feedOption) func() (feedOption, error) {
+	return func() (feedOption, error) {
+		return option, nil
+	}
+}
+
+func (s *show) explicit() (feedOption, error) {
+	if *s.Explicit {
+		return podcasts.Explicit, nil
+	}
+	return podcasts.Clean, nil
+}
+
+func (s *show) owner() (feedOption, error) {
+	return podcasts.Owner(s.Owner.Name, s.Owner.Email), nil
+}
+
+func (s *show) ttl() (feedOption, error) {
+	ttl, err := parseTTL(s.TTL)
+	if err != nil {
+		return nil, err
+	}
+	return podcasts.TTL(ttl), nil
+}
+
+func (s *show) skipDays() (feedOption, error) {
+	days := make([]time.Weekday, 0, len(s.SkipDays))
+	for _, name := range s.SkipDays {
+		day, err := parseWeekday(name)
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+	return podcasts.SkipDays(days...), nil
+}
+
+// dateOption returns a builder of the option taking the date parsed from value.
+func dateOption(value string, option func(t time.Time) feedOption) func() (feedOption, error) {
+	return func() (feedOption, error) {
+		date, err := podcasts.ParsePubDate(value)
+		if err != nil {
+			return nil, err
+		}
+		return option(date.Time), nil
+	}
+}
+
+// item maps the episode to a podcast item.
+func (e *episode) item() (*podcasts.Item, error) {
+	item := &podcasts.Item{
+		Title:       e.Title,
+		ItunesTitle: e.ItunesTitle,
+		GUID:        e.GUID,
+		Author:      e.Author,
+		Season:      e.Season,
+		Episode:     e.Episode,
+		EpisodeType: podcasts.EpisodeType(e.EpisodeType),
+		Subtitle:    e.Subtitle,
+		Enclosure:   e.enclosure(),
+	}
+	if e.Image != "" {
+		item.Image = &podcasts.ItunesImage{Href: e.Image}
+	}
+	// Markdown notes fill in the content, description and summary, unless they are set too.
+	settings := []itemSetting{
+		{"perma_link", e.PermaLink != nil && !*e.PermaLink, itemFixed(podcasts.NotPermaLink)},
+		{"pub_date", e.PubDate != "", e.pubDate},
+		{"duration", e.Duration != "", e.duration},
+		{"notes", e.Notes != "", itemFixed(podcasts.ShowNotes(e.Notes))},
+		{"description", e.Description != "", itemFixed(podcasts.ItemDescription(e.Description))},
+		{"content", e.Content != "", itemFixed(podcasts.ItemContent(e.Content))},
+		{"summary", e.Summary != "", itemFixed(podcasts.ItemSummary(e.Summary))},
+		{"block", e.Block, itemFixed(podcasts.ItemBlock)},
+		{"explicit", e.Explicit != nil, e.explicit},
+		{"closed_captioned", e.ClosedCaptioned, itemFixed(podcasts.ItemClosedCaptioned)},
+	}
+	for _, setting := range settings {
+		if !setting.set {
+			continue
+		}
+		option, err := setting.build()
+		if err == nil {
+			err = item.SetOptions(option)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", setting.key, err)
+		}
+	}
+	return item, nil
+}
+
+// itemFixed returns a builder of the given item option.
+func itemFixed(option itemOption) func() (itemOption, error) {
+	return func() (itemOption, error) {
+		return option, nil
+	}
+}
+
+func (e *episode) pubDate() (itemOption, error) {
+	pubDate, err := podcasts.ParsePubDate(e.PubDate)
+	if err != nil {
+		return nil, err
+	}
+	return podcasts.ItemPublished(pubDate.Time), nil
+}
+
+func (e *episode) duration() (itemOption, error) {
+	duration, err := podcasts.ParseDuration(e.Duration)
+	if err != nil {
+		return nil, err
+	}
+	return podcasts.ItemDuration(duration.Duration), nil
+}
+
+func (e *episode) explicit() (itemOption, error) {
+	if *e.Explicit {
+		return podcasts.ItemExplicit, nil
+	}
+	return podcasts.ItemClean, nil
+}
+
+func (e *episode) enclosure() *podcasts.Enclosure {
+	if e.Enclosure == nil {
+		return nil
+	}
+	return &podcasts.Enclosure{
+		URL:    e.Enclosure.URL,
+		Length: strconv.FormatInt(e.Enclosure.Length, 10),
+		Type:   e.Enclosure.Type,
+	}
+}
+
+// option returns the option adding the category, reporting its index on error.
+func (c *category) option(i int) func(f *podcasts.Feed) error {
+	subs := make([]podcasts.CategoryName, 0, len(c.Subcategories))
+	for _, sub := range c.Subcategories {
+		subs = append(subs, podcasts.CategoryName(sub))
+	}
+	option := podcasts.Category(podcasts.CategoryName(c.Text), subs...)
+	return func(f *podcasts.Feed) error {
+		if err := option(f); err != nil {
+			return fmt.Errorf("categories[%d]: %w", i, err)
+		}
+		return nil
+	}
+}
+
+// parseWeekday returns the day of the week with the given English name, ignoring case.
+func parseWeekday(name string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(name, day.String()) {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid day %q", name)
+}
+
+// parseTTL parses a ttl such as 90m or 1h, which must be a whole number of minutes
+// as the ttl of RSS is.
+func parseTTL(value string) (time.Duration, error) {
+	ttl, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if ttl <= 0 || ttl%time.Minute != 0 {
+		return 0, fmt.Errorf("invalid ttl %q, must be a whole number of minutes", value)
+	}
+	return ttl, nil
+}
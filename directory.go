@@ -0,0 +1,143 @@
+package podcasts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// mediaExtensions lists the extensions of the files added by FromDirectory.
+var mediaExtensions = map[string]bool{
+	".mp3":  true,
+	".m4a":  true,
+	".m4b":  true,
+	".mp4":  true,
+	".m4v":  true,
+	".ogg":  true,
+	".oga":  true,
+	".opus": true,
+}
+
+// tagDateLayouts lists the layouts of the recording dates found in ID3v2 and MP4 tags.
+var tagDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02T15",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// FromDirectory builds a podcast with an item for each MP3, MPEG-4 and Ogg
+// file in dir and its subdirectories, skipping hidden files.
+//
+// Items take their title, author, description, publication date and episode
+// number from the ID3v2 or MP4 tags of the file, falling back to the file name
+// and modification time. Enclosure URLs are the paths of the files relative to
+// dir, resolved against baseURL, and GUIDs are derived from the same relative
+// paths so that they are stable between runs.
+func FromDirectory(dir, baseURL string) (*Podcast, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if !base.IsAbs() {
+		return nil, ErrInvalidURL
+	}
+	p := &Podcast{
+		Title: filepath.Base(dir),
+		Link:  baseURL,
+	}
+	err = filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if file != dir && strings.HasPrefix(info.Name(), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() || !mediaExtensions[strings.ToLower(filepath.Ext(file))] {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		item, err := fileItem(file, filepath.ToSlash(rel), base, info.ModTime())
+		if err != nil {
+			return err
+		}
+		p.AddItem(item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// fileItem builds the item of the media file at given path relative to the podcast directory.
+func fileItem(file, rel string, base *url.URL, modified time.Time) (*Item, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	media, err := InspectMedia(f, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	tags, err := readTags(f, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	u := *base
+	u.Path = strings.TrimSuffix(base.Path, "/") + "/" + rel
+	u.RawPath = ""
+	sum := sha256.Sum256([]byte(rel))
+
+	item := &Item{
+		Title:           tags.Title,
+		GUID:            hex.EncodeToString(sum[:]),
+		GUIDIsPermaLink: "false",
+		PubDate:         NewPubDate(modified),
+		Author:          tags.Artist,
+		Duration:        NewDuration(media.Duration),
+		Episode:         tags.Track,
+		Enclosure:       media.Enclosure(u.String()),
+	}
+	if item.Title == "" {
+		item.Title = strings.TrimSuffix(path.Base(rel), path.Ext(rel))
+	}
+	if tags.Comment != "" {
+		item.Description = &CDATAText{Value: tags.Comment}
+	}
+	if date, ok := parseTagDate(tags.Date); ok {
+		item.PubDate = NewPubDate(date)
+	}
+	return item, nil
+}
+
+// parseTagDate parses the recording date of a tag, assuming UTC when it has no time zone.
+func parseTagDate(value string) (time.Time, bool) {
+	for _, layout := range tagDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
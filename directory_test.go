@@ -0,0 +1,121 @@
+package podcasts
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func writeTestFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestFromDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "My Show")
+	tagged := bytes.Join([][]byte{
+		id3v2(3,
+			id3v2Frame(3, "TIT2", latin1("Pilot")),
+			id3v2Frame(3, "TPE1", latin1("Host")),
+			id3v2Frame(3, "COMM", append([]byte{0, 'e', 'n', 'g', 0}, "The first one"...)),
+			id3v2Frame(3, "TYER", latin1("2024")),
+			id3v2Frame(3, "TDAT", latin1("1501")),
+			id3v2Frame(3, "TRCK", latin1("1")),
+		),
+		mp3Frames(mpeg1Header, 417, 100),
+	}, nil)
+	writeTestFile(t, filepath.Join(dir, "01 pilot.mp3"), tagged)
+	writeTestFile(t, filepath.Join(dir, "season 2", "bonus.mp3"), mp3Frames(mpeg1Header, 417, 10))
+	writeTestFile(t, filepath.Join(dir, "notes.txt"), []byte("not audio"))
+	writeTestFile(t, filepath.Join(dir, ".hidden.mp3"), []byte("hidden"))
+	writeTestFile(t, filepath.Join(dir, ".trash", "old.mp3"), []byte("hidden"))
+	modified := time.Date(2024, 2, 1, 8, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(filepath.Join(dir, "season 2", "bonus.mp3"), modified, modified); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	p, err := FromDirectory(dir, "https://example.com/files/")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if p.Title != "My Show" || p.Link != "https://example.com/files/" {
+		t.Errorf("unexpected podcast %+v", p)
+	}
+	if len(p.items) != 2 {
+		t.Fatalf("expected 2 items got %d", len(p.items))
+	}
+
+	first := p.items[0]
+	if first.Title != "Pilot" || first.Author != "Host" || first.Episode != 1 || first.Description.Value != "The first one" {
+		t.Errorf("unexpected item %+v", first)
+	}
+	if !first.PubDate.Equal(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected pubDate %v", first.PubDate.Time)
+	}
+	want := Enclosure{URL: "https://example.com/files/01%20pilot.mp3", Length: strconv.Itoa(len(tagged)), Type: MediaTypeMP3}
+	if *first.Enclosure != want {
+		t.Errorf("expected %+v got %+v", want, *first.Enclosure)
+	}
+	if first.Duration.Duration != samplesDuration(100*1152, 44100) {
+		t.Errorf("unexpected duration %v", first.Duration.Duration)
+	}
+	if first.GUIDIsPermaLink != "false" || len(first.GUID) != 64 {
+		t.Errorf("unexpected guid %v", first.GUID)
+	}
+
+	second := p.items[1]
+	if second.Title != "bonus" || second.Enclosure.URL != "https://example.com/files/season%202/bonus.mp3" {
+		t.Errorf("unexpected item %+v", second)
+	}
+	if !second.PubDate.Equal(modified) {
+		t.Errorf("expected %v got %v", modified, second.PubDate.Time)
+	}
+
+	again, err := FromDirectory(dir, "https://example.com/files")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if again.items[0].GUID != first.GUID || again.items[0].Enclosure.URL != first.Enclosure.URL {
+		t.Errorf("expected stable guid and url got %+v", again.items[0])
+	}
+}
+
+func TestFromDirectoryErrors(t *testing.T) {
+	if _, err := FromDirectory(t.TempDir(), "/relative"); err != ErrInvalidURL {
+		t.Errorf("expected %v got %v", ErrInvalidURL, err)
+	}
+	if _, err := FromDirectory(filepath.Join(t.TempDir(), "missing"), "https://example.com"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected %v got %v", os.ErrNotExist, err)
+	}
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "broken.mp3"), []byte("not really an mp3 file"))
+	if _, err := FromDirectory(dir, "https://example.com"); !errors.Is(err, ErrUnknownMedia) {
+		t.Errorf("expected %v got %v", ErrUnknownMedia, err)
+	}
+}
+
+func TestParseTagDate(t *testing.T) {
+	cases := map[string]time.Time{
+		"2024":                 time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		"2024-03":              time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		"2024-03-05T10:30":     time.Date(2024, 3, 5, 10, 30, 0, 0, time.UTC),
+		"2024-03-05T10:30:00Z": time.Date(2024, 3, 5, 10, 30, 0, 0, time.UTC),
+	}
+	for value, want := range cases {
+		if got, ok := parseTagDate(value); !ok || !got.Equal(want) {
+			t.Errorf("%q: expected %v got %v", value, want, got)
+		}
+	}
+	if _, ok := parseTagDate("someday"); ok {
+		t.Error("expected invalid date")
+	}
+}
@@ -10,7 +10,7 @@ Package podcasts implements a podcast generator.
 	    Copyright:   "2015 My podcast copyright",
 	}
 
-	// add first podcast item
+	// add a podcast item
 	p.AddItem(&podcasts.Item{
 	    Title:   "Episode 1",
 	    GUID:    "http://www.example-podcast.com/my-podcast/1/episode-one",
@@ -23,30 +23,13 @@ Package podcasts implements a podcast generator.
 	    },
 	})
 
-	// add second podcast item
-	p.AddItem(&podcasts.Item{
-	    Title:   "Episode 2",
-	    GUID:    "http://www.example-podcast.com/my-podcast/2/episode-two",
-	    PubDate: podcasts.NewPubDate(time.Now()),
-	    Duration: podcasts.NewDuration(time.Second * 210),
-	    Enclosure: &podcasts.Enclosure{
-	        URL:    "http://www.example-podcast.com/my-podcast/2/episode.mp3",
-	        Length: "46732",
-	        Type:   "MP3",
-	    },
-	})
-
 	// get podcast feed, you can pass options to customise it
 	feed, err := p.Feed(
 	    podcasts.Author("Author Name"),
-	    podcasts.Block,
 	    podcasts.Explicit,
-	    podcasts.Complete,
-	    podcasts.NewFeedURL("http://www.example-podcast.com/new-feed-url"),
-	    podcasts.Subtitle("This is my very simple podcast subtitle."),
-	    podcasts.Summary("This is my very simple podcast summary."),
 	    podcasts.Owner("Podcast Owner", "owner@example-podcast.com"),
 	    podcasts.Image("http://www.example-podcast.com/my-podcast.jpg"),
+	    podcasts.Category(podcasts.CategorySocietyAndCulture, podcasts.CategorySocietyDocumentary),
 	)
 
 	// handle error
@@ -56,5 +39,10 @@ Package podcasts implements a podcast generator.
 
 	// finally write the xml to any io.Writer
 	feed.Write(os.Stdout)
+
+Feeds can also be written as Atom or JSON Feed, streamed, paged, validated
+against the Apple Podcasts requirements, served over HTTP, and read back with
+Parse. Items can carry chapters and transcripts, and be built from media files
+or from show notes in Markdown. The examples show each of these.
 */
 package podcasts
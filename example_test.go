@@ -0,0 +1,319 @@
+package podcasts_test
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/CallumKerson/podcasts"
+)
+
+func examplePodcast() *podcasts.Podcast {
+	p := &podcasts.Podcast{
+		Title:       "My podcast",
+		Description: "This is my very simple podcast.",
+		Language:    "en",
+		Link:        "http://www.example-podcast.com/my-podcast",
+	}
+	for n := 1; n <= 3; n++ {
+		p.AddItem(&podcasts.Item{
+			Title:   fmt.Sprintf("Episode %d", n),
+			GUID:    fmt.Sprintf("http://www.example-podcast.com/my-podcast/%d", n),
+			PubDate: podcasts.NewPubDate(time.Date(2024, 1, n, 0, 0, 0, 0, time.UTC)),
+		})
+	}
+	return p
+}
+
+// TryAddItem reports items whose guid is already used when the podcast sets
+// the DuplicateError policy.
+func ExamplePodcast_TryAddItem() {
+	p := &podcasts.Podcast{Title: "My podcast", Duplicates: podcasts.DuplicateError}
+	item := &podcasts.Item{Title: "Episode 1", GUID: "http://www.example-podcast.com/my-podcast/1"}
+	if err := p.TryAddItem(item); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(p.TryAddItem(item))
+	// Output: podcasts: duplicate guid
+}
+
+// The same podcast can be written as RSS, Atom or JSON Feed.
+func ExampleFeed_WriteJSONFeed() {
+	feed, err := examplePodcast().Feed(podcasts.SelfLink("http://www.example-podcast.com/feed.json"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := feed.WriteJSONFeed(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Very large catalogs can be streamed, reading items one by one instead of
+// holding them all in memory.
+func ExampleFeed_WriteStream() {
+	feed, err := (&podcasts.Podcast{Title: "My podcast"}).Feed()
+	if err != nil {
+		log.Fatal(err)
+	}
+	n := 0
+	next := func() (*podcasts.Item, error) {
+		if n == 1000 {
+			return nil, io.EOF
+		}
+		n++
+		return podcasts.NewItem(fmt.Sprintf("Episode %d", n), fmt.Sprintf("http://www.example-podcast.com/my-podcast/%d", n))
+	}
+	if err := feed.WriteStream(io.Discard, next); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(n, "items")
+	// Output: 1000 items
+}
+
+// Large back catalogs can be split into RFC 5005 pages, rendered on demand.
+func ExamplePodcast_FeedPage() {
+	p := examplePodcast()
+	p.PageSize = 2
+	p.PageURL = func(page int) string {
+		return fmt.Sprintf("http://www.example-podcast.com/feed.xml?page=%d", page)
+	}
+	feed, err := p.FeedPage(2)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, item := range feed.Channel.Items {
+		fmt.Println(item.Title)
+	}
+	// Output: Episode 1
+}
+
+// Archived feeds keep the newest items in the subscription feed and the
+// older ones in archives, which never change once full.
+func ExamplePodcast_CurrentFeed() {
+	p := examplePodcast()
+	p.PageSize = 2
+	p.PageURL = func(page int) string {
+		return fmt.Sprintf("http://www.example-podcast.com/archive-%d.xml", page)
+	}
+	feed, err := p.CurrentFeed(podcasts.SelfLink("http://www.example-podcast.com/feed.xml"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, link := range feed.Channel.AtomLinks {
+		fmt.Println(link.Rel, link.Href)
+	}
+	fmt.Println(p.ArchiveCount(), "archive")
+	// Output:
+	// self http://www.example-podcast.com/feed.xml
+	// prev-archive http://www.example-podcast.com/archive-1.xml
+	// 1 archive
+}
+
+// Episodes uploaded in advance are left out of feeds until their pubDate.
+func ExamplePodcast_NextPublication() {
+	p := examplePodcast()
+	next, scheduled := p.NextPublication(time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC))
+	fmt.Println(next.Format(time.RFC3339), scheduled)
+	// Output: 2024-01-03T00:00:00Z true
+}
+
+// Options of NewItem validate their input like the feed options do.
+func ExampleNewItem() {
+	_, err := podcasts.NewItem("Episode 1", "http://www.example-podcast.com/my-podcast/1",
+		podcasts.ItemEnclosure("episode.mp3", 34216, podcasts.MediaTypeMP3),
+	)
+	fmt.Println(err)
+	// Output: podcasts: invalid url
+}
+
+// Chapters are written inline as Podlove Simple Chapters, and can also be
+// published as a Podcast Index JSON chapters file.
+func ExampleItemChapters() {
+	item, err := podcasts.NewItem("Episode 1", "http://www.example-podcast.com/my-podcast/1",
+		podcasts.ItemDuration(time.Hour),
+		podcasts.ItemChapters(
+			&podcasts.Chapter{Title: "Introduction"},
+			&podcasts.Chapter{Start: 5 * time.Minute, Title: "Interview"},
+		),
+		podcasts.Chapters("http://www.example-podcast.com/my-podcast/1/chapters.json", podcasts.ChaptersJSONType),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := item.SimpleChapters.WriteJSON(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+	// Output:
+	// {
+	//   "version": "1.2.0",
+	//   "chapters": [
+	//     {
+	//       "startTime": 0,
+	//       "title": "Introduction"
+	//     },
+	//     {
+	//       "startTime": 300,
+	//       "title": "Interview"
+	//     }
+	//   ]
+	// }
+}
+
+// A transcript can be converted to each format, and linked from the item
+// with ItemTranscripts.
+func ExampleCues_WriteSRT() {
+	cues, err := podcasts.ParseVTT(strings.NewReader("WEBVTT\n\n00:00.000 --> 00:02.500\n<v Alice>Welcome to the show.\n"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := cues.WriteSRT(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+	// Output:
+	// 1
+	// 00:00:00,000 --> 00:00:02,500
+	// Alice: Welcome to the show.
+}
+
+// Show notes written in Markdown are rendered to the HTML podcast apps
+// accept, with a plain-text description.
+func ExampleShowNotes() {
+	item, err := podcasts.NewItem("Episode 1", "http://www.example-podcast.com/my-podcast/1",
+		podcasts.ShowNotes("We talk about **show notes**, see [the docs](http://www.example-podcast.com/docs)."),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(item.ContentEncoded.Value)
+	fmt.Println(item.Description.Value)
+	// Output:
+	// <p>We talk about <b>show notes</b>, see <a href="http://www.example-podcast.com/docs">the docs</a>.</p>
+	// We talk about show notes, see the docs (http://www.example-podcast.com/docs).
+}
+
+func ExampleSanitizeHTML() {
+	fmt.Println(podcasts.SanitizeHTML(`<h1>Notes</h1><p onclick="x()">See <a href="javascript:x()">this</a></p><script>x()</script>`))
+	// Output:
+	// <p>Notes</p><p>See this</p>
+}
+
+// Characters not allowed in XML are replaced when writing CDATA, unless
+// SanitizeCDATA strips or rejects them.
+func ExampleSanitizeCDATA() {
+	p := &podcasts.Podcast{Title: "My podcast"}
+	p.AddItem(&podcasts.Item{Title: "Episode 1", Description: &podcasts.CDATAText{Value: "Bell \a"}})
+	_, err := p.Feed(podcasts.SanitizeCDATA(podcasts.CDATAReject))
+	fmt.Println(err)
+	// Output:
+	// podcasts: invalid character in CDATA: Channel.Items[0].Description
+}
+
+// Elements of other namespaces are written with the prefix declared on the feed.
+func ExampleDeclareNamespace() {
+	feed, err := (&podcasts.Podcast{Title: "My podcast"}).Feed(
+		podcasts.DeclareNamespace("googleplay", "http://www.google.com/schemas/play-podcasts/1.0"),
+		podcasts.ChannelExtensions(podcasts.NewExtension("googleplay:author", "Author Name")),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := feed.Write(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+	// Output:
+	// <?xml version="1.0" encoding="UTF-8"?>
+	// <rss xmlns:googleplay="http://www.google.com/schemas/play-podcasts/1.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd" xmlns:content="http://purl.org/rss/1.0/modules/content/" version="2.0">
+	//   <channel>
+	//     <title>My podcast</title>
+	//     <link></link>
+	//     <copyright></copyright>
+	//     <language></language>
+	//     <description></description>
+	//     <googleplay:author>Author Name</googleplay:author>
+	//   </channel>
+	// </rss>
+}
+
+// Existing feeds can be read back, for example to append an episode, keeping
+// the elements the package does not know.
+func ExampleParse() {
+	feed, err := podcasts.Parse(strings.NewReader(`<rss version="2.0"><channel><title>My podcast</title></channel></rss>`))
+	if err != nil {
+		log.Fatal(err)
+	}
+	feed.Channel.Items = append(feed.Channel.Items, &podcasts.Item{
+		Title: "Episode 1",
+		GUID:  "http://www.example-podcast.com/my-podcast/1",
+	})
+	fmt.Println(feed.Channel.Title, len(feed.Channel.Items))
+	// Output: My podcast 1
+}
+
+// Feeds can be checked against the Apple Podcasts requirements before they
+// are published.
+func ExampleFeed_Validate() {
+	feed, err := (&podcasts.Podcast{Title: "My podcast"}).Feed()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, problem := range feed.Validate().Errors() {
+		fmt.Println(problem)
+	}
+	// Output:
+	// error: Channel.Description: description is required
+	// error: Channel.Language: language is required
+	// error: Channel.Image: artwork is required
+	// error: Channel.Categories: at least one category is required
+	// error: Channel.Explicit: explicit is required
+}
+
+// Handler serves the feed with ETag, Last-Modified and gzip support, so
+// polling podcast apps only download it when it changes.
+func ExampleHandler() {
+	http.Handle("/feed.xml", &podcasts.Handler{
+		Podcast: examplePodcast(),
+		Options: []func(f *podcasts.Feed) error{podcasts.Author("Author Name")},
+	})
+}
+
+// Private feeds give each subscriber a signed feed url, and sign every
+// enclosure url with an expiring media token.
+func ExamplePrivateHandler() {
+	signer, err := podcasts.NewSigner([]byte("a secret key of at least 32 bytes"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	signer.Revocations = &podcasts.RevocationList{}
+	feedURL, err := signer.FeedURL("http://www.example-podcast.com/private.xml", "subscriber@example.com")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(strings.HasPrefix(feedURL, "http://www.example-podcast.com/private.xml?token="))
+	http.Handle("/private.xml", &podcasts.PrivateHandler{Podcast: examplePodcast(), Signer: signer})
+	http.Handle("/media/", signer.MediaHandler(http.FileServer(http.Dir("/srv/media"))))
+	// Output: true
+}
+
+// Enclosure length, type and duration are read from the media file itself.
+func ExampleInspectFile() {
+	enclosure, duration, err := podcasts.InspectFile("episode.mp3", "http://www.example-podcast.com/my-podcast/1/episode.mp3")
+	if err != nil {
+		log.Fatal(err)
+	}
+	examplePodcast().AddItem(&podcasts.Item{Title: "Episode 4", Enclosure: enclosure, Duration: duration})
+}
+
+// A show kept as a folder of audio files is read from the tags of each file.
+func ExampleFromDirectory() {
+	p, err := podcasts.FromDirectory("/srv/shows/my-podcast", "http://www.example-podcast.com/my-podcast/")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := p.Feed(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,220 @@
+package podcasts
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrInvalidNamespace represents a error returned for a namespace prefix
+	// or uri that cannot be declared.
+	ErrInvalidNamespace = errors.New("podcasts: invalid namespace")
+
+	// ErrInvalidExtension represents a error returned for an extension
+	// element or attribute whose name is not a prefixed XML name.
+	ErrInvalidExtension = errors.New("podcasts: invalid extension")
+)
+
+// Namespace represents a XML namespace declared on the rss element of given feed.
+type Namespace struct {
+	Prefix string
+	URI    string
+}
+
+// Extension represents an element of an extension namespace in given channel
+// or item, such as googleplay:author. XMLName is the prefixed name of the
+// element, e.g. xml.Name{Local: "googleplay:author"}, whose prefix is
+// declared with DeclareNamespace. Text is written before the children.
+type Extension struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr   `xml:",any,attr"`
+	Value    string       `xml:",chardata"`
+	Children []*Extension `xml:",any"`
+}
+
+// rawExtension is Extension without its XML methods.
+type rawExtension Extension
+
+// UnmarshalXML unmarshalls extension, dropping the white space between its children.
+func (e *Extension) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	var decoded rawExtension
+	if err := decoder.DecodeElement(&decoded, &start); err != nil {
+		return err
+	}
+	if len(decoded.Children) > 0 && strings.TrimSpace(decoded.Value) == "" {
+		decoded.Value = ""
+	}
+	*e = Extension(decoded)
+	return nil
+}
+
+// NewExtension returns a new extension element with given prefixed name, text and children.
+func NewExtension(name, value string, children ...*Extension) *Extension {
+	return &Extension{XMLName: xml.Name{Local: name}, Value: value, Children: children}
+}
+
+// DeclareNamespace declares a namespace on given feed, so that extension
+// elements and attributes can use its prefix. The namespaces of the package,
+// such as itunes or atom, are declared when used and do not need to be.
+func DeclareNamespace(prefix, uri string) func(f *Feed) error {
+	return func(f *Feed) error {
+		u, err := url.Parse(uri)
+		if err != nil || u.Scheme == "" || !isNCName(prefix) || strings.HasPrefix(strings.ToLower(prefix), "xml") {
+			return ErrInvalidNamespace
+		}
+		for _, ns := range builtinNamespaces {
+			if (ns.prefix == prefix) != (ns.uri == uri) {
+				return ErrInvalidNamespace
+			}
+		}
+		for _, ns := range f.Namespaces {
+			if ns != nil && ns.Prefix == prefix {
+				if ns.URI != uri {
+					return ErrInvalidNamespace
+				}
+				return nil
+			}
+		}
+		f.Namespaces = append(f.Namespaces, &Namespace{Prefix: prefix, URI: uri})
+		return nil
+	}
+}
+
+// ChannelExtensions adds extension elements to the channel of given feed.
+func ChannelExtensions(extensions ...*Extension) func(f *Feed) error {
+	return func(f *Feed) error {
+		if err := checkExtensions(extensions); err != nil {
+			return err
+		}
+		f.Channel.Extensions = append(f.Channel.Extensions, extensions...)
+		return nil
+	}
+}
+
+// ItemExtensions adds extension elements to given item.
+func ItemExtensions(extensions ...*Extension) func(i *Item) error {
+	return func(i *Item) error {
+		if err := checkExtensions(extensions); err != nil {
+			return err
+		}
+		i.Extensions = append(i.Extensions, extensions...)
+		return nil
+	}
+}
+
+// checkExtensions returns ErrInvalidExtension unless every element and
+// attribute of extensions has a prefixed name.
+func checkExtensions(extensions []*Extension) error {
+	for _, e := range extensions {
+		if e == nil || !isExtensionName(e.XMLName) {
+			return ErrInvalidExtension
+		}
+		for _, attr := range e.Attrs {
+			// Attributes without prefix belong to the element.
+			if !isExtensionName(attr.Name) && !(attr.Name.Space == "" && isNCName(attr.Name.Local)) {
+				return ErrInvalidExtension
+			}
+		}
+		if err := checkExtensions(e.Children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isExtensionName reports whether name is a prefixed XML name such as googleplay:author.
+func isExtensionName(name xml.Name) bool {
+	i := strings.IndexByte(name.Local, ':')
+	return name.Space == "" && i >= 0 && isNCName(name.Local[:i]) && isNCName(name.Local[i+1:])
+}
+
+// isNCName reports whether s is a XML name without colon.
+func isNCName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// prefixes returns the namespace prefixes declared on given feed or by the package.
+func (f *Feed) prefixes() map[string]bool {
+	prefixes := map[string]bool{"xml": true}
+	for _, ns := range builtinNamespaces {
+		prefixes[ns.prefix] = true
+	}
+	for _, ns := range f.Namespaces {
+		if ns != nil {
+			prefixes[ns.Prefix] = true
+		}
+	}
+	return prefixes
+}
+
+// extensionPrefixes returns the prefixes of the extension elements and
+// attributes of the channel and of its items.
+func extensionPrefixes(c *Channel) map[string]bool {
+	prefixes := make(map[string]bool)
+	if c == nil {
+		return prefixes
+	}
+	addExtensionPrefixes(prefixes, c.Extensions, c.ExtensionAttrs)
+	for _, item := range c.Items {
+		if item != nil {
+			addExtensionPrefixes(prefixes, item.Extensions, item.ExtensionAttrs)
+		}
+	}
+	return prefixes
+}
+
+func addExtensionPrefixes(prefixes map[string]bool, extensions []*Extension, attrs []xml.Attr) {
+	for _, attr := range attrs {
+		if i := strings.IndexByte(attr.Name.Local, ':'); i > 0 {
+			prefixes[attr.Name.Local[:i]] = true
+		}
+	}
+	for _, e := range extensions {
+		if e == nil {
+			continue
+		}
+		if i := strings.IndexByte(e.XMLName.Local, ':'); i > 0 {
+			prefixes[e.XMLName.Local[:i]] = true
+		}
+		addExtensionPrefixes(prefixes, e.Children, e.Attrs)
+	}
+}
+
+// cloneExtensions returns a deep copy of given extensions.
+func cloneExtensions(extensions []*Extension) []*Extension {
+	if extensions == nil {
+		return nil
+	}
+	clones := make([]*Extension, len(extensions))
+	for i, e := range extensions {
+		if e == nil {
+			continue
+		}
+		clone := *e
+		clone.Attrs = cloneAttrs(e.Attrs)
+		clone.Children = cloneExtensions(e.Children)
+		clones[i] = &clone
+	}
+	return clones
+}
+
+func cloneAttrs(attrs []xml.Attr) []xml.Attr {
+	if attrs == nil {
+		return nil
+	}
+	return append([]xml.Attr(nil), attrs...)
+}
@@ -0,0 +1,300 @@
+package podcasts
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const googlePlayXMLNS = "http://www.google.com/schemas/play-podcasts/1.0"
+
+func TestDeclareNamespace(t *testing.T) {
+	cases := map[string][2]string{
+		"NoScheme":       {"googleplay", "www.google.com/schemas/play-podcasts/1.0"},
+		"EmptyPrefix":    {"", googlePlayXMLNS},
+		"InvalidPrefix":  {"google play", googlePlayXMLNS},
+		"ReservedPrefix": {"xmlns", googlePlayXMLNS},
+		"BuiltinPrefix":  {"itunes", googlePlayXMLNS},
+		"BuiltinURI":     {"apple", itunesXMLNS},
+		"Redeclared":     {"googleplay", "https://example.com/other"},
+	}
+	for name, ns := range cases {
+		t.Run(name, func(t *testing.T) {
+			feed := &Feed{Channel: &Channel{}}
+			err := feed.SetOptions(DeclareNamespace("googleplay", googlePlayXMLNS), DeclareNamespace(ns[0], ns[1]))
+			if !errors.Is(err, ErrInvalidNamespace) {
+				t.Errorf("expected %v got %v", ErrInvalidNamespace, err)
+			}
+		})
+	}
+
+	feed := &Feed{Channel: &Channel{}}
+	err := feed.SetOptions(
+		DeclareNamespace("googleplay", googlePlayXMLNS),
+		DeclareNamespace("googleplay", googlePlayXMLNS),
+		DeclareNamespace("itunes", itunesXMLNS),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := []*Namespace{{Prefix: "googleplay", URI: googlePlayXMLNS}, {Prefix: "itunes", URI: itunesXMLNS}}
+	if !reflect.DeepEqual(feed.Namespaces, want) {
+		t.Errorf("expected %+v got %+v", want, feed.Namespaces)
+	}
+}
+
+func TestExtensionOptionsErrors(t *testing.T) {
+	cases := map[string]*Extension{
+		"Nil":             nil,
+		"NoPrefix":        NewExtension("author", "Author"),
+		"EmptyLocal":      NewExtension("googleplay:", "Author"),
+		"Namespaced":      {XMLName: xml.Name{Space: googlePlayXMLNS, Local: "author"}},
+		"InvalidAttr":     {XMLName: xml.Name{Local: "googleplay:image"}, Attrs: []xml.Attr{{Name: xml.Name{Local: "a b"}}}},
+		"InvalidChildren": NewExtension("googleplay:owner", "", NewExtension("email", "owner@example.com")),
+	}
+	for name, extension := range cases {
+		t.Run(name, func(t *testing.T) {
+			feed := &Feed{Channel: &Channel{}}
+			if err := feed.SetOptions(ChannelExtensions(extension)); !errors.Is(err, ErrInvalidExtension) {
+				t.Errorf("expected %v got %v", ErrInvalidExtension, err)
+			}
+			if _, err := NewItem("Episode 1", "https://example.com/1", ItemExtensions(extension)); !errors.Is(err, ErrInvalidExtension) {
+				t.Errorf("expected %v got %v", ErrInvalidExtension, err)
+			}
+		})
+	}
+}
+
+func setupExtensionFeed(t *testing.T) *Feed {
+	t.Helper()
+	p := &Podcast{Title: "Extensions", Link: "https://example.com", Description: "Extended"}
+	item, err := NewItem("Episode 1", "https://example.com/1",
+		ItemExtensions(NewExtension("googleplay:explicit", "no")),
+		func(i *Item) error {
+			i.ExtensionAttrs = []xml.Attr{{Name: xml.Name{Local: "media:id"}, Value: "1"}}
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	p.AddItem(item)
+	feed, err := p.Feed(
+		DeclareNamespace("googleplay", googlePlayXMLNS),
+		DeclareNamespace("media", "http://search.yahoo.com/mrss/"),
+		ChannelExtensions(
+			NewExtension("googleplay:author", "Author Name"),
+			&Extension{
+				XMLName: xml.Name{Local: "googleplay:image"},
+				Attrs:   []xml.Attr{{Name: xml.Name{Local: "href"}, Value: "https://example.com/image.jpg"}},
+			},
+			NewExtension("googleplay:owner", "", NewExtension("googleplay:email", "owner@example.com")),
+		),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return feed
+}
+
+func TestExtensionsXML(t *testing.T) {
+	feed := setupExtensionFeed(t)
+	output, err := feed.XML()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, want := range []string{
+		`<rss xmlns:googleplay="` + googlePlayXMLNS + `" xmlns:media="http://search.yahoo.com/mrss/" xmlns:itunes="` + itunesXMLNS + `" xmlns:content="` + contentXMLNS + `" version="2.0">`,
+		`<googleplay:author>Author Name</googleplay:author>`,
+		`<googleplay:image href="https://example.com/image.jpg"></googleplay:image>`,
+		`<googleplay:email>owner@example.com</googleplay:email>`,
+		`<item media:id="1">`,
+		`<googleplay:explicit>no</googleplay:explicit>`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %v to contain %v", output, want)
+		}
+	}
+	for _, problem := range feed.Validate() {
+		if strings.Contains(problem.Message, "is not declared") {
+			t.Errorf("unexpected problem %v", problem)
+		}
+	}
+}
+
+func TestExtensionsRoundTrip(t *testing.T) {
+	feed := setupExtensionFeed(t)
+	want, err := feed.XML()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	parsed, err := Parse(strings.NewReader(want))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(parsed.Channel.Extensions, feed.Channel.Extensions) {
+		t.Errorf("expected %+v got %+v", feed.Channel.Extensions, parsed.Channel.Extensions)
+	}
+	item := parsed.Channel.Items[0]
+	if !reflect.DeepEqual(item.Extensions, feed.Channel.Items[0].Extensions) {
+		t.Errorf("expected %+v got %+v", feed.Channel.Items[0].Extensions, item.Extensions)
+	}
+	if !reflect.DeepEqual(item.ExtensionAttrs, feed.Channel.Items[0].ExtensionAttrs) {
+		t.Errorf("expected %+v got %+v", feed.Channel.Items[0].ExtensionAttrs, item.ExtensionAttrs)
+	}
+	got, err := parsed.XML()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got != want {
+		t.Errorf("expected %v got %v", want, got)
+	}
+}
+
+func TestParseExtensionNamespaces(t *testing.T) {
+	data := `<rss xmlns:gp="` + googlePlayXMLNS + `" version="2.0"><channel>
+<gp:author>Author Name</gp:author>
+<image><url>https://example.com/image.jpg</url></image>
+<item xmlns:itunes="https://example.com/not-itunes" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<title>Episode 1</title>
+<itunes:rating xml:lang="en">5</itunes:rating>
+<dc:creator>Author Name</dc:creator>
+</item>
+</channel></rss>`
+	feed, err := Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	wantNamespaces := []*Namespace{
+		{Prefix: "gp", URI: googlePlayXMLNS},
+		{Prefix: "ns1", URI: "https://example.com/not-itunes"},
+		{Prefix: "dc", URI: "http://purl.org/dc/elements/1.1/"},
+	}
+	if !reflect.DeepEqual(feed.Namespaces, wantNamespaces) {
+		t.Errorf("expected %+v got %+v", wantNamespaces, feed.Namespaces)
+	}
+	var names []string
+	for _, e := range feed.Channel.Extensions {
+		names = append(names, e.XMLName.Local)
+	}
+	for _, e := range feed.Channel.Items[0].Extensions {
+		names = append(names, e.XMLName.Local)
+	}
+	if got, want := strings.Join(names, ", "), "gp:author, image, ns1:rating, dc:creator"; got != want {
+		t.Errorf("expected %v got %v", want, got)
+	}
+	rating := feed.Channel.Items[0].Extensions[0]
+	if want := []xml.Attr{{Name: xml.Name{Local: "xml:lang"}, Value: "en"}}; !reflect.DeepEqual(rating.Attrs, want) {
+		t.Errorf("expected %+v got %+v", want, rating.Attrs)
+	}
+	if feed.Channel.Items[0].ExtensionAttrs != nil {
+		t.Errorf("expected namespace declarations to be dropped got %+v", feed.Channel.Items[0].ExtensionAttrs)
+	}
+
+	output, err := feed.XML()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, want := range []string{
+		`xmlns:ns1="https://example.com/not-itunes"`,
+		`<ns1:rating xml:lang="en">5</ns1:rating>`,
+		`<url>https://example.com/image.jpg</url>`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %v to contain %v", output, want)
+		}
+	}
+	if _, err := Parse(strings.NewReader(output)); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestWriteStreamExtensions(t *testing.T) {
+	feed := setupExtensionFeed(t)
+	items := feed.Channel.Items
+	feed.Channel.Items = nil
+	var buf bytes.Buffer
+	if err := feed.WriteStream(&buf, SliceItems(items)); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	parsed, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(parsed.Channel.Extensions, feed.Channel.Extensions) {
+		t.Errorf("expected %+v got %+v", feed.Channel.Extensions, parsed.Channel.Extensions)
+	}
+	if !reflect.DeepEqual(parsed.Channel.Items[0].Extensions, items[0].Extensions) {
+		t.Errorf("expected %+v got %+v", items[0].Extensions, parsed.Channel.Items[0].Extensions)
+	}
+	if !parsed.declares("googleplay") || !parsed.declares("media") {
+		t.Errorf("expected googleplay and media to be declared got %+v", parsed.Namespaces)
+	}
+}
+
+func TestExtensionUsesBuiltinNamespace(t *testing.T) {
+	feed := &Feed{Version: rssVersion, Channel: &Channel{Title: "Title"}}
+	if err := feed.SetOptions(ChannelExtensions(NewExtension("podcast:guid", "ead4c236-bf58-58c6-a2c6-a6b28d128cb6"))); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	output, err := feed.XML()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if want := `xmlns:podcast="` + podcastXMLNS + `"`; !strings.Contains(output, want) {
+		t.Errorf("expected %v to contain %v", output, want)
+	}
+}
+
+func TestValidateExtensions(t *testing.T) {
+	feed := &Feed{Channel: &Channel{
+		Extensions: []*Extension{NewExtension("googleplay:owner", "", NewExtension("googleplay:email", "owner@example.com"))},
+		Items: []*Item{{
+			Extensions:     []*Extension{{XMLName: xml.Name{Local: "itunes:keywords"}, Attrs: []xml.Attr{{Name: xml.Name{Local: "media:id"}}}}},
+			ExtensionAttrs: []xml.Attr{{Name: xml.Name{Local: "xml:lang"}, Value: "en"}},
+		}},
+	}}
+	want := "Channel.Extensions[0], Channel.Extensions[0].Children[0], Channel.Items[0].Extensions[0].Attrs[0]"
+	if got := undeclaredFields(feed); got != want {
+		t.Errorf("expected %v got %v", want, got)
+	}
+
+	if err := feed.SetOptions(DeclareNamespace("googleplay", googlePlayXMLNS)); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got, want := undeclaredFields(feed), "Channel.Items[0].Extensions[0].Attrs[0]"; got != want {
+		t.Errorf("expected %v got %v", want, got)
+	}
+}
+
+func undeclaredFields(feed *Feed) string {
+	var fields []string
+	for _, problem := range feed.Validate().Errors() {
+		if strings.Contains(problem.Message, "is not declared") {
+			fields = append(fields, problem.Field)
+		}
+	}
+	return strings.Join(fields, ", ")
+}
+
+func TestItemsCloneExtensions(t *testing.T) {
+	p := &Podcast{}
+	item := &Item{
+		GUID:           "https://example.com/1",
+		Extensions:     []*Extension{NewExtension("googleplay:owner", "", NewExtension("googleplay:email", "owner@example.com"))},
+		ExtensionAttrs: []xml.Attr{{Name: xml.Name{Local: "media:id"}, Value: "1"}},
+	}
+	p.AddItem(item)
+	clone := p.Items()[0]
+	clone.Extensions[0].Children[0].Value = "changed@example.com"
+	clone.ExtensionAttrs[0].Value = "2"
+	if got := item.Extensions[0].Children[0].Value; got != "owner@example.com" {
+		t.Errorf("expected %v got %v", "owner@example.com", got)
+	}
+	if got := item.ExtensionAttrs[0].Value; got != "1" {
+		t.Errorf("expected %v got %v", "1", got)
+	}
+}
@@ -12,6 +12,7 @@ import (
 const (
 	itunesXMLNS  = "http://www.itunes.com/dtds/podcast-1.0.dtd"
 	contentXMLNS = "http://purl.org/rss/1.0/modules/content/"
+	atomXMLNS    = "http://www.w3.org/2005/Atom"
 	rssVersion   = "2.0"
 	rfc2822      = "Mon, 02 Jan 2006 15:04:05 -0700"
 )
@@ -37,6 +38,20 @@ func (p PubDate) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error
 	return encoder.EncodeToken(xml.EndElement{Name: start.Name})
 }
 
+// UnmarshalXML unmarshalls pubdate using ParsePubDate.
+func (p *PubDate) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	var value string
+	if err := decoder.DecodeElement(&value, &start); err != nil {
+		return err
+	}
+	parsed, err := ParsePubDate(value)
+	if err != nil {
+		return err
+	}
+	*p = *parsed
+	return nil
+}
+
 // NewDuration returns a new Duration.
 func NewDuration(d time.Duration) *Duration {
 	return &Duration{d}
@@ -58,6 +73,20 @@ func (d Duration) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error
 	return encoder.EncodeToken(xml.EndElement{Name: start.Name})
 }
 
+// UnmarshalXML unmarshalls duration using ParseDuration.
+func (d *Duration) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	var value string
+	if err := decoder.DecodeElement(&value, &start); err != nil {
+		return err
+	}
+	parsed, err := ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = *parsed
+	return nil
+}
+
 // formatDuration formats duration in these formats: HH:MM:SS, H:MM:SS, MM:SS, M:SS.
 func formatDuration(d time.Duration) string {
 	total := int(d.Seconds())
@@ -96,9 +125,9 @@ type ItunesImage struct {
 
 // ItunesCategory represents itunes:category of given channel.
 type ItunesCategory struct {
-	XMLName    xml.Name `xml:"itunes:category"`
-	Text       string   `xml:"text,attr"`
-	Categories []*ItunesCategory
+	XMLName    xml.Name          `xml:"itunes:category"`
+	Text       string            `xml:"text,attr"`
+	Categories []*ItunesCategory `xml:"itunes:category"`
 }
 
 // Enclosure represents audio or video file of given item.
@@ -109,60 +138,307 @@ type Enclosure struct {
 	Type    string   `xml:"type,attr"`
 }
 
+// AtomLink represents atom:link of given channel.
+type AtomLink struct {
+	XMLName xml.Name `xml:"atom:link"`
+	Href    string   `xml:"href,attr"`
+	Rel     string   `xml:"rel,attr,omitempty"`
+	Type    string   `xml:"type,attr,omitempty"`
+}
+
+// SkipHourList represents skipHours of given channel, the hours in GMT
+// during which aggregators may skip reading the feed.
+type SkipHourList struct {
+	XMLName xml.Name `xml:"skipHours"`
+	Hours   []int    `xml:"hour"`
+}
+
+// SkipDayList represents skipDays of given channel, the days
+// during which aggregators may skip reading the feed.
+type SkipDayList struct {
+	XMLName xml.Name `xml:"skipDays"`
+	Days    []string `xml:"day"`
+}
+
 // CDATAText represents some content that may contain
 // embedded HTML such as <a href="...">...</a> links.
 type CDATAText struct {
 	Value string `xml:",cdata"`
 }
 
+// ShowType represents itunes:type of given channel.
+type ShowType string
+
+const (
+	// Episodic shows are presented newest episode first.
+	Episodic ShowType = "episodic"
+	// Serial shows are presented oldest episode first, grouped by season.
+	Serial ShowType = "serial"
+)
+
+// EpisodeType represents itunes:episodeType of given item.
+type EpisodeType string
+
+const (
+	// EpisodeFull is a complete episode.
+	EpisodeFull EpisodeType = "full"
+	// EpisodeTrailer is a short promotional episode.
+	EpisodeTrailer EpisodeType = "trailer"
+	// EpisodeBonus is extra content for a show or season.
+	EpisodeBonus EpisodeType = "bonus"
+)
+
 // Item represents item of given channel.
 type Item struct {
-	XMLName         xml.Name   `xml:"item"`
-	Title           string     `xml:"title"`
-	GUID            string     `xml:"guid"`
-	PubDate         *PubDate   `xml:"pubDate"`
-	Description     *CDATAText `xml:"description,omitempty"`
-	ContentEncoded  *CDATAText `xml:"content:encoded,omitempty"`
+	XMLName         xml.Name    `xml:"item"`
+	Title           string      `xml:"title"`
+	ItunesTitle     string      `xml:"itunes:title,omitempty"`
+	GUID            string      `xml:"guid"`
+	GUIDIsPermaLink string      `xml:"-"`
+	PubDate         *PubDate    `xml:"pubDate"`
+	Description     *CDATAText  `xml:"description,omitempty"`
+	ContentEncoded  *CDATAText  `xml:"content:encoded,omitempty"`
+	Author          string      `xml:"itunes:author,omitempty"`
+	Block           string      `xml:"itunes:block,omitempty"`
+	Duration        *Duration   `xml:"itunes:duration,omitempty"`
+	Explicit        string      `xml:"itunes:explicit,omitempty"`
+	ClosedCaptioned string      `xml:"itunes:isClosedCaptioned,omitempty"`
+	Order           int         `xml:"itunes:order,omitempty"`
+	Season          int         `xml:"itunes:season,omitempty"`
+	Episode         int         `xml:"itunes:episode,omitempty"`
+	EpisodeType     EpisodeType `xml:"itunes:episodeType,omitempty"`
+	Subtitle        string      `xml:"itunes:subtitle,omitempty"`
+	Summary         *CDATAText  `xml:"itunes:summary,omitempty"`
+	Enclosure       *Enclosure
+	Image           *ItunesImage
+	Transcripts     []*PodcastTranscript `xml:"podcast:transcript"`
+	Chapters        *PodcastChapters
+	SimpleChapters  *SimpleChapters
+	// Extensions are the elements of extension namespaces, and any other
+	// element not read into the fields above when parsing.
+	Extensions     []*Extension `xml:",any"`
+	ExtensionAttrs []xml.Attr   `xml:",any,attr"`
+}
+
+// rawItem is Item without its XML methods.
+type rawItem Item
+
+// itemGUID represents guid of given item.
+type itemGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink string `xml:"isPermaLink,attr,omitempty"`
+}
+
+// MarshalXML marshalls item, writing GUIDIsPermaLink as the isPermaLink attribute of guid.
+func (i Item) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	// Title and ItunesTitle are repeated so that guid keeps its place after them.
+	return encoder.EncodeElement(struct {
+		XMLName     xml.Name  `xml:"item"`
+		Title       string    `xml:"title"`
+		ItunesTitle string    `xml:"itunes:title,omitempty"`
+		GUID        *itemGUID `xml:"guid"`
+		rawItem
+	}{
+		Title:       i.Title,
+		ItunesTitle: i.ItunesTitle,
+		GUID:        &itemGUID{Value: i.GUID, IsPermaLink: i.GUIDIsPermaLink},
+		rawItem:     rawItem(i),
+	}, start)
+}
+
+// UnmarshalXML unmarshalls item, reading the isPermaLink attribute of guid
+// into GUIDIsPermaLink. A pubDate or itunes:duration that cannot be read is
+// left out, rather than failing the whole feed.
+func (i *Item) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	var decoded struct {
+		GUID     *itemGUID `xml:"guid"`
+		PubDate  *string   `xml:"pubDate"`
+		Duration *string   `xml:"itunes:duration"`
+		rawItem
+	}
+	if err := decoder.DecodeElement(&decoded, &start); err != nil {
+		return err
+	}
+	*i = Item(decoded.rawItem)
+	if decoded.GUID != nil {
+		i.GUID = decoded.GUID.Value
+		i.GUIDIsPermaLink = decoded.GUID.IsPermaLink
+	}
+	i.PubDate = lenientPubDate(decoded.PubDate)
+	if decoded.Duration != nil {
+		i.Duration, _ = ParseDuration(*decoded.Duration)
+	}
+	return nil
+}
+
+// lenientPubDate returns the pubDate read from value, or nil if there is
+// none or it cannot be read.
+func lenientPubDate(value *string) *PubDate {
+	if value == nil {
+		return nil
+	}
+	p, err := ParsePubDate(*value)
+	if err != nil {
+		return nil
+	}
+	return p
+}
+
+// Channel represents a RSS channel for given podcast.
+type Channel struct {
+	XMLName         xml.Name    `xml:"channel"`
+	Title           string      `xml:"title"`
+	Link            string      `xml:"link"`
+	Copyright       string      `xml:"copyright"`
+	Language        string      `xml:"language"`
+	Description     string      `xml:"description"`
+	AtomLinks       []*AtomLink `xml:"atom:link"`
+	ManagingEditor  string      `xml:"managingEditor,omitempty"`
+	WebMaster       string      `xml:"webMaster,omitempty"`
+	PubDate         *PubDate    `xml:"pubDate,omitempty"`
+	LastBuildDate   *PubDate    `xml:"lastBuildDate,omitempty"`
+	Generator       string      `xml:"generator,omitempty"`
+	Docs            string      `xml:"docs,omitempty"`
+	TTL             int         `xml:"ttl,omitempty"`
+	SkipHours       *SkipHourList
+	SkipDays        *SkipDayList
 	Author          string     `xml:"itunes:author,omitempty"`
+	Type            ShowType   `xml:"itunes:type,omitempty"`
 	Block           string     `xml:"itunes:block,omitempty"`
-	Duration        *Duration  `xml:"itunes:duration,omitempty"`
 	Explicit        string     `xml:"itunes:explicit,omitempty"`
-	ClosedCaptioned string     `xml:"itunes:isClosedCaptioned,omitempty"`
-	Order           int        `xml:"itunes:order,omitempty"`
+	Complete        string     `xml:"itunes:complete,omitempty"`
+	NewFeedURL      string     `xml:"itunes:new-feed-url,omitempty"`
 	Subtitle        string     `xml:"itunes:subtitle,omitempty"`
 	Summary         *CDATAText `xml:"itunes:summary,omitempty"`
-	Enclosure       *Enclosure
+	Owner           *ItunesOwner
 	Image           *ItunesImage
+	Locked          *PodcastLocked
+	Funding         []*PodcastFunding `xml:"podcast:funding"`
+	HistoryComplete *HistoryFlag      `xml:"fh:complete"`
+	HistoryArchive  *HistoryFlag      `xml:"fh:archive"`
+	Items           []*Item           `xml:"item"`
+	Categories      []*ItunesCategory `xml:"itunes:category"`
+	// Extensions are the elements of extension namespaces, and any other
+	// element not read into the fields above when parsing.
+	Extensions     []*Extension `xml:",any"`
+	ExtensionAttrs []xml.Attr   `xml:",any,attr"`
 }
 
-// Channel represents a RSS channel for given podcast.
-type Channel struct {
-	XMLName     xml.Name   `xml:"channel"`
-	Title       string     `xml:"title"`
-	Link        string     `xml:"link"`
-	Copyright   string     `xml:"copyright"`
-	Language    string     `xml:"language"`
-	Description string     `xml:"description"`
-	Author      string     `xml:"itunes:author,omitempty"`
-	Block       string     `xml:"itunes:block,omitempty"`
-	Explicit    string     `xml:"itunes:explicit,omitempty"`
-	Complete    string     `xml:"itunes:complete,omitempty"`
-	NewFeedURL  string     `xml:"itunes:new-feed-url,omitempty"`
-	Subtitle    string     `xml:"itunes:subtitle,omitempty"`
-	Summary     *CDATAText `xml:"itunes:summary,omitempty"`
-	Owner       *ItunesOwner
-	Image       *ItunesImage
-	Items       []*Item
-	Categories  []*ItunesCategory
+// UnmarshalXML unmarshalls channel. A pubDate or lastBuildDate that cannot be
+// read is left out, rather than failing the whole feed.
+func (c *Channel) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	var decoded struct {
+		PubDate       *string `xml:"pubDate"`
+		LastBuildDate *string `xml:"lastBuildDate"`
+		rawChannel
+	}
+	if err := decoder.DecodeElement(&decoded, &start); err != nil {
+		return err
+	}
+	*c = Channel(decoded.rawChannel)
+	c.PubDate = lenientPubDate(decoded.PubDate)
+	c.LastBuildDate = lenientPubDate(decoded.LastBuildDate)
+	return nil
 }
 
 // Feed wraps the given RSS channel.
 type Feed struct {
-	XMLName      xml.Name `xml:"rss"`
-	ItunesXMLNS  string   `xml:"xmlns:itunes,attr"`
-	ContentXMLNS string   `xml:"xmlns:content,attr"`
-	Version      string   `xml:"version,attr"`
-	Channel      *Channel
+	XMLName xml.Name `xml:"rss"`
+	Version string   `xml:"version,attr"`
+	// Namespaces are declared on the rss element, before the namespaces of
+	// the package, such as itunes and content, which are declared when used.
+	Namespaces []*Namespace `xml:"-"`
+	Channel    *Channel
+}
+
+// builtinNamespace represents a XML namespace of the elements of the package,
+// declared on the feed when used.
+type builtinNamespace struct {
+	prefix string
+	uri    string
+	used   func(c *Channel) bool
+	// items is true for namespaces of item elements, always declared by
+	// streamed feeds whose items are not known in advance.
+	items bool
+}
+
+// builtinNamespaces lists the namespaces of the elements of the package.
+var builtinNamespaces = []builtinNamespace{
+	{prefix: "itunes", uri: itunesXMLNS, used: always, items: true},
+	{prefix: "content", uri: contentXMLNS, used: always, items: true},
+	{prefix: "atom", uri: atomXMLNS, used: usesAtomNamespace},
+	{prefix: "podcast", uri: podcastXMLNS, used: usesPodcastNamespace, items: true},
+	{prefix: "fh", uri: historyXMLNS, used: usesHistoryNamespace},
+	{prefix: "psc", uri: pscXMLNS, used: usesPSCNamespace, items: true},
+}
+
+// always is used by the itunes and content namespaces, declared by every feed.
+func always(c *Channel) bool {
+	return true
+}
+
+// usesAtomNamespace reports whether any atom: element is set on the channel.
+func usesAtomNamespace(c *Channel) bool {
+	return len(c.AtomLinks) > 0
+}
+
+// MarshalXML marshalls feed, declaring the namespaces of the package only when used.
+func (f Feed) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	extensions := extensionPrefixes(f.Channel)
+	start = f.startElement(func(ns builtinNamespace) bool {
+		return f.Channel != nil && (ns.used(f.Channel) || extensions[ns.prefix])
+	})
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	if f.Channel != nil {
+		if err := encoder.Encode(f.Channel); err != nil {
+			return err
+		}
+	}
+	return encoder.EncodeToken(xml.EndElement{Name: start.Name})
+}
+
+// startElement returns the rss element of the feed, declaring its namespaces
+// and the namespaces of the package for which used is true.
+func (f *Feed) startElement(used func(ns builtinNamespace) bool) xml.StartElement {
+	start := xml.StartElement{Name: xml.Name{Local: "rss"}}
+	declared := make(map[string]bool, len(f.Namespaces))
+	for _, ns := range f.Namespaces {
+		if ns == nil || declared[ns.Prefix] {
+			continue
+		}
+		declared[ns.Prefix] = true
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns:" + ns.Prefix}, Value: ns.URI})
+	}
+	for _, ns := range builtinNamespaces {
+		if !declared[ns.prefix] && used(ns) {
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns:" + ns.prefix}, Value: ns.uri})
+		}
+	}
+	start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "version"}, Value: f.Version})
+	return start
+}
+
+// UnmarshalXML unmarshalls feed, reading the namespaces declared on the rss element into Namespaces.
+func (f *Feed) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	if start.Name.Space != "" || start.Name.Local != "rss" {
+		return xml.UnmarshalError("expected element type <rss> but have <" + start.Name.Local + ">")
+	}
+	var decoded struct {
+		Version string `xml:"version,attr"`
+		Channel *Channel
+	}
+	if err := decoder.DecodeElement(&decoded, &start); err != nil {
+		return err
+	}
+	*f = Feed{XMLName: start.Name, Version: decoded.Version, Channel: decoded.Channel}
+	for _, attr := range start.Attr {
+		if prefix := strings.TrimPrefix(attr.Name.Local, "xmlns:"); attr.Name.Space == "" && prefix != attr.Name.Local {
+			f.Namespaces = append(f.Namespaces, &Namespace{Prefix: prefix, URI: attr.Value})
+		}
+	}
+	return nil
 }
 
 // SetOptions sets options of given feed.
@@ -2,7 +2,10 @@ package podcasts
 
 import (
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand"
 	"strings"
 	"testing"
 	"time"
@@ -174,6 +177,136 @@ func TestFuzzPodcastCreation(t *testing.T) {
 	}
 }
 
+// cdataFragments are the pieces random CDATA content is built from
+var cdataFragments = []string{
+	"]]>", "]]", "]", ">", "<![CDATA[", "<p>", "</p>", "&amp;", "&", "<", "\"", "'",
+	"\x00", "\x01", "\x08", "\x0b", "\x0c", "\x1b", "\x7f", "\t", "\r\n", "\n",
+	"\xed\xa0\x80", "\xed\xbf\xbf", "\xff", "\xc3", "\xef\xbf\xbe", "\xef\xbf\xbf",
+	"\ufffd", "\u2028", "é", "🎙️", "text", " ",
+}
+
+// randomCDATA returns random content built from cdataFragments
+func randomCDATA(r *rand.Rand) string {
+	var b strings.Builder
+	for n := r.Intn(12); n >= 0; n-- {
+		b.WriteString(cdataFragments[r.Intn(len(cdataFragments))])
+	}
+	return b.String()
+}
+
+// TestFuzzCDATA tests that random CDATA content always produces well-formed XML
+func TestFuzzCDATA(t *testing.T) {
+	// Seeded, so that failures can be reproduced
+	r := rand.New(rand.NewSource(2015))
+
+	for i := 0; i < 500; i++ {
+		description := randomCDATA(r)
+		content := randomCDATA(r)
+		summary := randomCDATA(r)
+
+		podcast := &Podcast{Title: "Fuzz"}
+		podcast.AddItem(&Item{
+			Title:          "Episode",
+			GUID:           fmt.Sprintf("guid-%d", i),
+			Description:    &CDATAText{description},
+			ContentEncoded: &CDATAText{content},
+			Summary:        &CDATAText{summary},
+		})
+		feed, err := podcast.Feed(Summary(summary))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		xmlContent, err := feed.XML()
+		if err != nil {
+			t.Fatalf("%q: XML generation failed: %v", description, err)
+		}
+
+		// Re-parse with a strict decoder, then with Parse
+		decoder := xml.NewDecoder(strings.NewReader(xmlContent))
+		for {
+			_, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%q %q %q: malformed XML %v", description, content, summary, err)
+			}
+		}
+		parsed, err := Parse(strings.NewReader(xmlContent))
+		if err != nil {
+			t.Fatalf("%q: parse failed: %v", description, err)
+		}
+
+		item := parsed.Channel.Items[0]
+		for _, c := range []struct{ got, value string }{
+			{item.Description.Value, description},
+			{item.ContentEncoded.Value, content},
+			{item.Summary.Value, summary},
+			{parsed.Channel.Summary.Value, summary},
+		} {
+			// XML parsers normalise line endings to \n
+			want, _ := sanitizeCDATA(c.value, CDATAReplace)
+			want = strings.ReplaceAll(strings.ReplaceAll(want, "\r\n", "\n"), "\r", "\n")
+			if c.got != want {
+				t.Errorf("expected %q got %q", want, c.got)
+			}
+		}
+
+		// Every policy either sanitizes the content or rejects it
+		for _, policy := range []CDATAPolicy{CDATAReplace, CDATAStrip, CDATAReject} {
+			feed, err := podcast.Feed(SanitizeCDATA(policy))
+			if err != nil {
+				if policy != CDATAReject || !errors.Is(err, ErrInvalidCDATA) {
+					t.Errorf("%q: unexpected error %v", description, err)
+				}
+				continue
+			}
+			if _, err := feed.XML(); err != nil {
+				t.Errorf("%q: XML generation failed: %v", description, err)
+			}
+			if policy != CDATAReplace && invalidXMLIndex(feed.Channel.Items[0].Description.Value) >= 0 {
+				t.Errorf("%q: expected sanitized description", description)
+			}
+		}
+	}
+}
+
+// showNotesFragments are the pieces random show notes are built from
+var showNotesFragments = []string{
+	"*", "**", "_", "__", "`", "\\", "[", "]", "(", ")", "![", "<", ">", "&", "&amp;", "&#x1F3A7;",
+	"# ", "- ", "1. ", "> ", "```", "---", "\n", "\n\n", "  \n", "\t",
+	"<p>", "</p>", "<b>", "</i>", "<li>", "<ul>", "<script>", "</script>", "<!--", "-->", "<a href=\"",
+	"https://example.com/", "javascript:", "mailto:a@example.com", "\"", "'", "=", "/",
+	"word", " ", "é", "\xff",
+}
+
+// TestFuzzShowNotes tests that random show notes render to sanitized HTML
+func TestFuzzShowNotes(t *testing.T) {
+	// Seeded, so that failures can be reproduced
+	r := rand.New(rand.NewSource(2015))
+
+	for i := 0; i < 2000; i++ {
+		var b strings.Builder
+		for n := r.Intn(30); n >= 0; n-- {
+			b.WriteString(showNotesFragments[r.Intn(len(showNotesFragments))])
+		}
+		notes := b.String()
+
+		rendered := RenderMarkdown(notes)
+		if sanitized := SanitizeHTML(rendered); sanitized != rendered {
+			t.Fatalf("%q: expected rendered HTML %q to be sanitized, got %q", notes, rendered, sanitized)
+		}
+		sanitized := SanitizeHTML(notes)
+		if again := SanitizeHTML(sanitized); again != sanitized {
+			t.Fatalf("%q: expected sanitizing to be idempotent, got %q then %q", notes, sanitized, again)
+		}
+		_ = HTMLText(notes)
+		if text := truncateText(HTMLText(rendered), 100); utf8.RuneCountInString(text) > 100 {
+			t.Fatalf("%q: expected at most 100 characters got %q", notes, text)
+		}
+	}
+}
+
 // Helper functions for fuzzing tests
 func minInt(a, b int) int {
 	if a < b {
@@ -0,0 +1,169 @@
+package podcasts
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// rssContentType is the Content-Type of the served feed.
+	rssContentType = "application/rss+xml; charset=utf-8"
+)
+
+// Handler serves the feed of a podcast over HTTP.
+//
+// Responses carry a strong ETag and a Last-Modified date taken from the
+// newest item, so polling clients get a 304 Not Modified when nothing
+// changed, and are compressed with gzip when the client accepts it.
+//
+// Items scheduled in the future are left out of the feed. While any is
+// scheduled, responses expire when the next one is published, with
+// Cache-Control max-age and Expires.
+type Handler struct {
+	// Podcast is the podcast whose feed is served.
+	Podcast *Podcast
+	// Options customise the served feed, as in Podcast.Feed.
+	Options []func(f *Feed) error
+
+	gzip gzipCache
+}
+
+// gzipCache holds the last gzipped feed and the ETag of its uncompressed body.
+type gzipCache struct {
+	mu   sync.Mutex
+	etag string
+	body []byte
+}
+
+// ServeHTTP serves the feed in response to GET and HEAD requests.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveFeed(w, r, &h.gzip, h.Podcast, h.Options, true)
+}
+
+// serveFeed serves the feed of the podcast with given options, compressing it
+// with cache, or without caching if cache is nil. Last-Modified is only sent
+// if lastModified is set.
+func serveFeed(w http.ResponseWriter, r *http.Request, cache *gzipCache, podcast *Podcast,
+	options []func(f *Feed) error, lastModified bool) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	if podcast == nil {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	now := time.Now()
+	feed, err := podcast.FeedAt(now, options...)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	var buf bytes.Buffer
+	if err := feed.Write(&buf); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	body := buf.Bytes()
+
+	header := w.Header()
+	header.Set("Content-Type", rssContentType)
+	header.Add("Vary", "Accept-Encoding")
+	if acceptsGzip(r.Header.Get("Accept-Encoding")) {
+		// The compressed body is a different representation, so it needs its own strong ETag.
+		body, err = cache.compress(etag, body)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		etag = strings.TrimSuffix(etag, `"`) + `-gzip"`
+		header.Set("Content-Encoding", "gzip")
+	}
+	header.Set("ETag", etag)
+	if next, ok := podcast.NextPublication(now); ok {
+		// Caches in front of the handler must not serve the feed past the next scheduled item.
+		cacheControl := "max-age=" + strconv.FormatInt(int64(next.Sub(now)/time.Second), 10)
+		if existing := header.Get("Cache-Control"); existing != "" {
+			cacheControl = existing + ", " + cacheControl
+		}
+		header.Set("Cache-Control", cacheControl)
+		header.Set("Expires", next.UTC().Format(http.TimeFormat))
+	}
+
+	var modified time.Time
+	if latest := latestPubDate(feed.Channel.Items); latest != nil && lastModified {
+		modified = latest.Time
+	}
+	http.ServeContent(w, r, "", modified, bytes.NewReader(body))
+}
+
+// compress returns the gzipped body, reusing the last result while the feed
+// is unchanged. A nil cache compresses every body.
+func (c *gzipCache) compress(etag string, body []byte) ([]byte, error) {
+	if c == nil {
+		return gzipBody(body)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.etag == etag {
+		return c.body, nil
+	}
+	gzipped, err := gzipBody(body)
+	if err != nil {
+		return nil, err
+	}
+	c.etag, c.body = etag, gzipped
+	return c.body, nil
+}
+
+func gzipBody(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// acceptsGzip reports whether the Accept-Encoding header allows a gzip response.
+func acceptsGzip(header string) bool {
+	gzipQ, wildcardQ := -1.0, -1.0
+	for _, part := range strings.Split(header, ",") {
+		coding, q := part, 1.0
+		if i := strings.Index(part, ";"); i >= 0 {
+			coding = part[:i]
+			param := strings.TrimSpace(part[i+1:])
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			var err error
+			if q, err = strconv.ParseFloat(param[len("q="):], 64); err != nil {
+				continue
+			}
+		}
+		switch strings.ToLower(strings.TrimSpace(coding)) {
+		case "gzip", "x-gzip":
+			gzipQ = q
+		case "*":
+			wildcardQ = q
+		}
+	}
+	if gzipQ >= 0 {
+		return gzipQ > 0
+	}
+	return wildcardQ > 0
+}
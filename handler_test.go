@@ -0,0 +1,211 @@
+package podcasts
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func setupHandler() *Handler {
+	podcast := &Podcast{Title: "Handler Podcast", Link: "https://example.com"}
+	podcast.AddItem(&Item{
+		Title:   "Episode 1",
+		GUID:    "https://example.com/1",
+		PubDate: NewPubDate(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)),
+	})
+	podcast.AddItem(&Item{
+		Title:   "Episode 2",
+		GUID:    "https://example.com/2",
+		PubDate: NewPubDate(time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)),
+	})
+	return &Handler{Podcast: podcast, Options: []func(f *Feed) error{Author(testAuthor)}}
+}
+
+func serve(h http.Handler, method string, header map[string]string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, "/feed.xml", nil)
+	for key, value := range header {
+		r.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestHandlerGet(t *testing.T) {
+	h := setupHandler()
+	w := serve(h, http.MethodGet, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %v got %v", http.StatusOK, w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != rssContentType {
+		t.Errorf("expected %v got %v", rssContentType, got)
+	}
+	if got := w.Header().Get("Last-Modified"); got != "Thu, 01 Feb 2024 12:00:00 GMT" {
+		t.Errorf("expected %v got %v", "Thu, 01 Feb 2024 12:00:00 GMT", got)
+	}
+	if got := w.Header().Get("Vary"); got != "Accept-Encoding" {
+		t.Errorf("expected %v got %v", "Accept-Encoding", got)
+	}
+	etag := w.Header().Get("ETag")
+	if len(etag) < 3 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		t.Errorf("expected strong etag got %v", etag)
+	}
+	feed, _ := h.Podcast.Feed(h.Options...)
+	want, _ := feed.XML()
+	if got := w.Body.String(); got != want {
+		t.Errorf("expected %v got %v", want, got)
+	}
+
+	if again := serve(h, http.MethodGet, nil).Header().Get("ETag"); again != etag {
+		t.Errorf("expected stable etag %v got %v", etag, again)
+	}
+	h.Podcast.AddItem(&Item{Title: "Episode 3", GUID: "https://example.com/3"})
+	if changed := serve(h, http.MethodGet, nil).Header().Get("ETag"); changed == etag {
+		t.Errorf("expected etag to change after adding an item")
+	}
+}
+
+func TestHandlerNotModified(t *testing.T) {
+	h := setupHandler()
+	etag := serve(h, http.MethodGet, nil).Header().Get("ETag")
+	cases := map[string]struct {
+		header map[string]string
+		want   int
+	}{
+		"IfNoneMatch":         {map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		"IfNoneMatchList":     {map[string]string{"If-None-Match": `"other", ` + etag}, http.StatusNotModified},
+		"IfNoneMatchWildcard": {map[string]string{"If-None-Match": "*"}, http.StatusNotModified},
+		"IfNoneMatchStale":    {map[string]string{"If-None-Match": `"stale"`}, http.StatusOK},
+		"IfModifiedSince":     {map[string]string{"If-Modified-Since": "Thu, 01 Feb 2024 12:00:00 GMT"}, http.StatusNotModified},
+		"IfModifiedSinceOld":  {map[string]string{"If-Modified-Since": "Mon, 01 Jan 2024 12:00:00 GMT"}, http.StatusOK},
+		"IfNoneMatchWins": {map[string]string{
+			"If-None-Match":     `"stale"`,
+			"If-Modified-Since": "Thu, 01 Feb 2024 12:00:00 GMT",
+		}, http.StatusOK},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := serve(h, http.MethodGet, c.header)
+			if w.Code != c.want {
+				t.Errorf("expected %v got %v", c.want, w.Code)
+			}
+			if c.want == http.StatusNotModified && w.Body.Len() != 0 {
+				t.Errorf("expected empty body got %v", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandlerHead(t *testing.T) {
+	h := setupHandler()
+	get := serve(h, http.MethodGet, nil)
+	head := serve(h, http.MethodHead, nil)
+	if head.Code != http.StatusOK {
+		t.Fatalf("expected %v got %v", http.StatusOK, head.Code)
+	}
+	if head.Body.Len() != 0 {
+		t.Errorf("expected empty body got %v", head.Body.String())
+	}
+	if head.Header().Get("ETag") != get.Header().Get("ETag") {
+		t.Errorf("expected %v got %v", get.Header().Get("ETag"), head.Header().Get("ETag"))
+	}
+	if head.Header().Get("Content-Length") != get.Header().Get("Content-Length") {
+		t.Errorf("expected %v got %v", get.Header().Get("Content-Length"), head.Header().Get("Content-Length"))
+	}
+}
+
+func TestHandlerGzip(t *testing.T) {
+	h := setupHandler()
+	plain := serve(h, http.MethodGet, nil)
+	w := serve(h, http.MethodGet, map[string]string{"Accept-Encoding": "br;q=1.0, gzip;q=0.8"})
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("expected %v got %v", "gzip", got)
+	}
+	etag := w.Header().Get("ETag")
+	if etag == plain.Header().Get("ETag") {
+		t.Errorf("expected gzip etag to differ from %v", etag)
+	}
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	body, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !bytes.Equal(body, plain.Body.Bytes()) {
+		t.Errorf("expected %v got %v", plain.Body.String(), string(body))
+	}
+
+	notModified := serve(h, http.MethodGet, map[string]string{"Accept-Encoding": "gzip", "If-None-Match": etag})
+	if notModified.Code != http.StatusNotModified {
+		t.Errorf("expected %v got %v", http.StatusNotModified, notModified.Code)
+	}
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	cases := map[string]bool{
+		"":                     false,
+		"gzip":                 true,
+		"GZIP":                 true,
+		"deflate, gzip":        true,
+		"gzip;q=0":             false,
+		"gzip;q=0.5":           true,
+		"*":                    true,
+		"*;q=0":                false,
+		"gzip;q=0, *":          false,
+		"identity, *;q=0.1":    true,
+		"br":                   false,
+		"x-gzip":               true,
+		"gzip;q=invalid, br":   false,
+		"deflate;q=1, *;q=0.0": false,
+	}
+	for header, want := range cases {
+		if got := acceptsGzip(header); got != want {
+			t.Errorf("%q: expected %v got %v", header, want, got)
+		}
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+	if w := serve(setupHandler(), http.MethodPost, nil); w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "GET, HEAD" {
+		t.Errorf("expected %v got %v", http.StatusMethodNotAllowed, w.Code)
+	}
+	if w := serve(&Handler{}, http.MethodGet, nil); w.Code != http.StatusNotFound {
+		t.Errorf("expected %v got %v", http.StatusNotFound, w.Code)
+	}
+	h := setupHandler()
+	h.Options = append(h.Options, Type("invalid"))
+	if w := serve(h, http.MethodGet, nil); w.Code != http.StatusInternalServerError {
+		t.Errorf("expected %v got %v", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestHandlerScheduled(t *testing.T) {
+	h := setupHandler()
+	w := serve(h, http.MethodGet, nil)
+	if got := w.Header().Get("Cache-Control"); got != "" {
+		t.Errorf("expected no Cache-Control got %v", got)
+	}
+
+	next := time.Now().Add(time.Hour).Truncate(time.Second)
+	h.Podcast.AddItem(&Item{Title: "Episode 3", GUID: "https://example.com/3", PubDate: NewPubDate(next)})
+	w = serve(h, http.MethodGet, nil)
+	if bytes.Contains(w.Body.Bytes(), []byte("Episode 3")) {
+		t.Error("expected scheduled item to be left out")
+	}
+	if got := w.Header().Get("Last-Modified"); got != "Thu, 01 Feb 2024 12:00:00 GMT" {
+		t.Errorf("expected %v got %v", "Thu, 01 Feb 2024 12:00:00 GMT", got)
+	}
+	if got, want := w.Header().Get("Expires"), next.UTC().Format(http.TimeFormat); got != want {
+		t.Errorf("expected %v got %v", want, got)
+	}
+	maxAge := w.Header().Get("Cache-Control")
+	if maxAge != "max-age=3599" && maxAge != "max-age=3598" {
+		t.Errorf("expected max-age of about an hour got %v", maxAge)
+	}
+}
@@ -0,0 +1,261 @@
+package podcasts
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// historyXMLNS is the namespace of RFC 5005 feed paging and archiving.
+const historyXMLNS = "http://purl.org/syndication/history/1.0"
+
+var (
+	// ErrInvalidPage represents a error returned for a page number out of range.
+	ErrInvalidPage = errors.New("podcasts: invalid page")
+
+	// ErrInvalidPaging represents a error returned when PageSize or PageURL of the podcast is not set.
+	ErrInvalidPaging = errors.New("podcasts: invalid paging")
+)
+
+// HistoryFlag represents an empty element of the feed history namespace, fh:complete or fh:archive.
+type HistoryFlag struct{}
+
+// HistoryComplete enables fh:complete of given feed, telling clients that the
+// feed holds every item, so that items missing from it were removed.
+func HistoryComplete(f *Feed) error {
+	f.Channel.HistoryComplete = &HistoryFlag{}
+	return nil
+}
+
+// usesHistoryNamespace reports whether any fh: element is set on the channel.
+func usesHistoryNamespace(c *Channel) bool {
+	return c.HistoryComplete != nil || c.HistoryArchive != nil
+}
+
+// PageCount returns the number of pages of the paged feed, at least 1.
+func (p *Podcast) PageCount() int {
+	return p.PageCountAt(time.Now())
+}
+
+// PageCountAt returns the number of pages of the paged feed as it is at given time.
+func (p *Podcast) PageCountAt(now time.Time) int {
+	return p.pageCount(len(p.publishedItems(now)))
+}
+
+func (p *Podcast) pageCount(items int) int {
+	if p.PageSize <= 0 || items == 0 {
+		return 1
+	}
+	return (items + p.PageSize - 1) / p.PageSize
+}
+
+// FeedPage creates the feed of given page of the podcast, paged as described
+// in RFC 5005 section 3. Page 1 holds the newest PageSize items and each
+// following page the next older ones. Items are split into pages by pubDate
+// whatever the Order of the podcast, which only sorts the items of each page,
+// newest first for InsertionOrder.
+//
+// Pages link to each other with atom:link rel="first", "last", "prev" and
+// "next", and to themselves with rel="self", using the urls returned by PageURL.
+func (p *Podcast) FeedPage(page int, options ...func(f *Feed) error) (*Feed, error) {
+	return p.FeedPageAt(time.Now(), page, options...)
+}
+
+// FeedPageAt creates the feed of given page of the podcast as it is at given
+// time, leaving out items with a pubDate after now.
+func (p *Podcast) FeedPageAt(now time.Time, page int, options ...func(f *Feed) error) (*Feed, error) {
+	if p.PageSize <= 0 || p.PageURL == nil {
+		return nil, ErrInvalidPaging
+	}
+	items := chronological(p.publishedItems(now))
+	count := p.pageCount(len(items))
+	if page < 1 || page > count {
+		return nil, ErrInvalidPage
+	}
+	end := len(items) - (page-1)*p.PageSize
+	start := end - p.PageSize
+	if start < 0 {
+		start = 0
+	}
+	feed, err := p.feed(p.pageOrder(items[start:end]), options...)
+	if err != nil {
+		return feed, err
+	}
+	links := []*AtomLink{
+		historyLink(p.PageURL(page), "self"),
+		historyLink(p.PageURL(1), "first"),
+		historyLink(p.PageURL(count), "last"),
+	}
+	if page > 1 {
+		links = append(links, historyLink(p.PageURL(page-1), "prev"))
+	}
+	if page < count {
+		links = append(links, historyLink(p.PageURL(page+1), "next"))
+	}
+	setHistoryLinks(feed.Channel, links)
+	return feed, nil
+}
+
+// ArchiveCount returns the number of archives of the archived feed.
+func (p *Podcast) ArchiveCount() int {
+	return p.ArchiveCountAt(time.Now())
+}
+
+// ArchiveCountAt returns the number of archives of the archived feed as it is at given time.
+func (p *Podcast) ArchiveCountAt(now time.Time) int {
+	return p.archiveCount(len(p.publishedItems(now)))
+}
+
+func (p *Podcast) archiveCount(items int) int {
+	if p.PageSize <= 0 || items <= p.PageSize {
+		return 0
+	}
+	return (items - 1) / p.PageSize
+}
+
+// CurrentFeed creates the subscription feed of the podcast archived as
+// described in RFC 5005 section 4. It holds the newest PageSize items, and
+// links to the newest archive with atom:link rel="prev-archive".
+//
+// Older items are archived oldest first in pages of PageSize items, the
+// newest archive holding the remainder, so that an archive never changes
+// once full. As with FeedPage, the Order of the podcast only sorts the items
+// of each feed.
+func (p *Podcast) CurrentFeed(options ...func(f *Feed) error) (*Feed, error) {
+	return p.CurrentFeedAt(time.Now(), options...)
+}
+
+// CurrentFeedAt creates the subscription feed of the archived podcast as it
+// is at given time, leaving out items with a pubDate after now.
+func (p *Podcast) CurrentFeedAt(now time.Time, options ...func(f *Feed) error) (*Feed, error) {
+	if p.PageSize <= 0 || p.PageURL == nil {
+		return nil, ErrInvalidPaging
+	}
+	items := chronological(p.publishedItems(now))
+	count := p.archiveCount(len(items))
+	start := len(items) - p.PageSize
+	if start < 0 {
+		start = 0
+	}
+	feed, err := p.feed(p.pageOrder(items[start:]), options...)
+	if err != nil {
+		return feed, err
+	}
+	if count > 0 {
+		feed.Channel.AtomLinks = append(feed.Channel.AtomLinks, historyLink(p.PageURL(count), "prev-archive"))
+	}
+	return feed, nil
+}
+
+// FeedArchive creates the feed of given archive of the podcast, archived as
+// described in RFC 5005 section 4. Archive 1 holds the oldest PageSize items,
+// and the newest archive the items older than the current feed left over.
+//
+// Archives are marked with fh:archive, link to the subscription feed, whose
+// url is taken from the SelfLink option, with atom:link rel="current", and to
+// each other with rel="prev-archive" and "next-archive".
+func (p *Podcast) FeedArchive(archive int, options ...func(f *Feed) error) (*Feed, error) {
+	return p.FeedArchiveAt(time.Now(), archive, options...)
+}
+
+// FeedArchiveAt creates the feed of given archive of the podcast as it is at
+// given time, leaving out items with a pubDate after now.
+func (p *Podcast) FeedArchiveAt(now time.Time, archive int, options ...func(f *Feed) error) (*Feed, error) {
+	if p.PageSize <= 0 || p.PageURL == nil {
+		return nil, ErrInvalidPaging
+	}
+	items := chronological(p.publishedItems(now))
+	count := p.archiveCount(len(items))
+	if archive < 1 || archive > count {
+		return nil, ErrInvalidPage
+	}
+	end := archive * p.PageSize
+	if archived := len(items) - p.PageSize; end > archived {
+		end = archived
+	}
+	feed, err := p.feed(p.pageOrder(items[(archive-1)*p.PageSize:end]), options...)
+	if err != nil {
+		return feed, err
+	}
+	var current string
+	for _, link := range feed.Channel.AtomLinks {
+		if link != nil && link.Rel == "self" {
+			current = link.Href
+		}
+	}
+	links := []*AtomLink{historyLink(p.PageURL(archive), "self")}
+	if current != "" {
+		links = append(links, historyLink(current, "current"))
+	}
+	if archive > 1 {
+		links = append(links, historyLink(p.PageURL(archive-1), "prev-archive"))
+	}
+	if archive < count {
+		links = append(links, historyLink(p.PageURL(archive+1), "next-archive"))
+	}
+	setHistoryLinks(feed.Channel, links)
+	feed.Channel.HistoryArchive = &HistoryFlag{}
+	return feed, nil
+}
+
+// publishedItems returns the items of the podcast published at given time.
+func (p *Podcast) publishedItems(now time.Time) []*Item {
+	return published(p.snapshot(), now)
+}
+
+// pageOrder returns the chronological items of a page or archive sorted in
+// the Order of the podcast, newest first for InsertionOrder.
+func (p *Podcast) pageOrder(items []*Item) []*Item {
+	if p.Order == InsertionOrder {
+		return newestFirst(items)
+	}
+	return sortItems(items, p.Order)
+}
+
+// chronological returns a copy of given items oldest first. Items without
+// pubDate are the oldest, and items published at the same time keep their order.
+func chronological(snapshot []*Item) []*Item {
+	items := make([]*Item, len(snapshot))
+	copy(items, snapshot)
+	sort.SliceStable(items, func(i, j int) bool {
+		return pubDateBefore(items[i], items[j])
+	})
+	return items
+}
+
+// pubDateBefore reports whether item a was published before item b.
+func pubDateBefore(a, b *Item) bool {
+	if a == nil || a.PubDate == nil {
+		return b != nil && b.PubDate != nil
+	}
+	return b != nil && b.PubDate != nil && a.PubDate.Before(b.PubDate.Time)
+}
+
+// newestFirst returns a copy of the chronological items in reverse order.
+func newestFirst(items []*Item) []*Item {
+	reversed := make([]*Item, len(items))
+	for i, item := range items {
+		reversed[len(items)-1-i] = item
+	}
+	return reversed
+}
+
+func historyLink(href, rel string) *AtomLink {
+	return &AtomLink{Href: href, Rel: rel, Type: "application/rss+xml"}
+}
+
+// setHistoryLinks replaces the self and paging links of the channel.
+func setHistoryLinks(c *Channel, links []*AtomLink) {
+	kept := make([]*AtomLink, 0, len(c.AtomLinks)+len(links))
+	for _, link := range c.AtomLinks {
+		if link == nil {
+			continue
+		}
+		switch link.Rel {
+		case "self", "first", "last", "prev", "next", "current", "prev-archive", "next-archive":
+		default:
+			kept = append(kept, link)
+		}
+	}
+	c.AtomLinks = append(kept, links...)
+}
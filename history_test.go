@@ -0,0 +1,273 @@
+package podcasts
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupPagedPodcast(n int) *Podcast {
+	p := &Podcast{
+		Title:    "Paged",
+		PageSize: 2,
+		PageURL: func(page int) string {
+			return fmt.Sprintf("https://example.com/feed.xml?page=%d", page)
+		},
+	}
+	// Items are added out of order to check that pages are sorted by pubDate.
+	for i := n; i >= 1; i-- {
+		p.AddItem(&Item{
+			Title:   fmt.Sprintf("Episode %d", i),
+			GUID:    fmt.Sprintf("https://example.com/%d", i),
+			PubDate: NewPubDate(time.Date(2024, 1, i, 0, 0, 0, 0, time.UTC)),
+		})
+	}
+	return p
+}
+
+func titles(items []*Item) string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.Title)
+	}
+	return strings.Join(names, ", ")
+}
+
+func links(c *Channel) map[string]string {
+	m := make(map[string]string)
+	for _, link := range c.AtomLinks {
+		m[link.Rel] = link.Href
+	}
+	return m
+}
+
+func TestFeedPage(t *testing.T) {
+	p := setupPagedPodcast(5)
+	if count := p.PageCount(); count != 3 {
+		t.Fatalf("expected 3 pages got %d", count)
+	}
+	cases := []struct {
+		page  int
+		items string
+		links map[string]string
+	}{
+		{1, "Episode 5, Episode 4", map[string]string{
+			"self":  "https://example.com/feed.xml?page=1",
+			"first": "https://example.com/feed.xml?page=1",
+			"last":  "https://example.com/feed.xml?page=3",
+			"next":  "https://example.com/feed.xml?page=2",
+		}},
+		{2, "Episode 3, Episode 2", map[string]string{
+			"self":  "https://example.com/feed.xml?page=2",
+			"first": "https://example.com/feed.xml?page=1",
+			"last":  "https://example.com/feed.xml?page=3",
+			"prev":  "https://example.com/feed.xml?page=1",
+			"next":  "https://example.com/feed.xml?page=3",
+		}},
+		{3, "Episode 1", map[string]string{
+			"self":  "https://example.com/feed.xml?page=3",
+			"first": "https://example.com/feed.xml?page=1",
+			"last":  "https://example.com/feed.xml?page=3",
+			"prev":  "https://example.com/feed.xml?page=2",
+		}},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprint(c.page), func(t *testing.T) {
+			feed, err := p.FeedPage(c.page, SelfLink("https://example.com/feed.xml"))
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if got := titles(feed.Channel.Items); got != c.items {
+				t.Errorf("expected %v got %v", c.items, got)
+			}
+			if got := links(feed.Channel); fmt.Sprint(got) != fmt.Sprint(c.links) {
+				t.Errorf("expected %v got %v", c.links, got)
+			}
+			if !feed.Channel.LastBuildDate.Equal(feed.Channel.Items[0].PubDate.Time) {
+				t.Errorf("expected lastBuildDate of newest item on page got %v", feed.Channel.LastBuildDate.Time)
+			}
+		})
+	}
+}
+
+func TestFeedArchive(t *testing.T) {
+	p := setupPagedPodcast(5)
+	if count := p.ArchiveCount(); count != 2 {
+		t.Fatalf("expected 2 archives got %d", count)
+	}
+
+	current, err := p.CurrentFeed(SelfLink("https://example.com/feed.xml"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := titles(current.Channel.Items); got != "Episode 5, Episode 4" {
+		t.Errorf("expected %v got %v", "Episode 5, Episode 4", got)
+	}
+	want := map[string]string{
+		"self":         "https://example.com/feed.xml",
+		"prev-archive": "https://example.com/feed.xml?page=2",
+	}
+	if got := links(current.Channel); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("expected %v got %v", want, got)
+	}
+	if current.Channel.HistoryArchive != nil {
+		t.Error("expected current feed not to be an archive")
+	}
+
+	archive, err := p.FeedArchive(1, SelfLink("https://example.com/feed.xml"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := titles(archive.Channel.Items); got != "Episode 2, Episode 1" {
+		t.Errorf("expected %v got %v", "Episode 2, Episode 1", got)
+	}
+	want = map[string]string{
+		"self":         "https://example.com/feed.xml?page=1",
+		"current":      "https://example.com/feed.xml",
+		"next-archive": "https://example.com/feed.xml?page=2",
+	}
+	if got := links(archive.Channel); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("expected %v got %v", want, got)
+	}
+	data, err := archive.XML()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !strings.Contains(data, `xmlns:fh="`+historyXMLNS+`"`) || !strings.Contains(data, "<fh:archive></fh:archive>") {
+		t.Errorf("expected %v to contain fh:archive", data)
+	}
+
+	if archive, _ := p.FeedArchive(2); titles(archive.Channel.Items) != "Episode 3" {
+		t.Errorf("expected %v got %v", "Episode 3", titles(archive.Channel.Items))
+	}
+
+	// Adding items must not change full archives.
+	p.AddItem(&Item{Title: "Episode 6", PubDate: NewPubDate(time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC))})
+	again, err := p.FeedArchive(1, SelfLink("https://example.com/feed.xml"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got, _ := again.XML(); got != data {
+		t.Errorf("expected %v got %v", data, got)
+	}
+	if p.ArchiveCount() != 2 {
+		t.Errorf("expected 2 archives got %d", p.ArchiveCount())
+	}
+	if archive, _ := p.FeedArchive(2); titles(archive.Channel.Items) != "Episode 4, Episode 3" {
+		t.Errorf("expected %v got %v", "Episode 4, Episode 3", titles(archive.Channel.Items))
+	}
+	// The current feed holds a full page when the items fill whole pages.
+	if current, _ := p.CurrentFeed(); titles(current.Channel.Items) != "Episode 6, Episode 5" {
+		t.Errorf("expected %v got %v", "Episode 6, Episode 5", titles(current.Channel.Items))
+	}
+}
+
+func TestFeedPageAt(t *testing.T) {
+	p := setupPagedPodcast(5)
+	// Episodes 4 and 5 are not published yet.
+	now := time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC)
+	if count := p.PageCountAt(now); count != 2 {
+		t.Errorf("expected 2 pages got %d", count)
+	}
+	if count := p.ArchiveCountAt(now); count != 1 {
+		t.Errorf("expected 1 archive got %d", count)
+	}
+	page, err := p.FeedPageAt(now, 1)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := titles(page.Channel.Items); got != "Episode 3, Episode 2" {
+		t.Errorf("expected %v got %v", "Episode 3, Episode 2", got)
+	}
+	current, err := p.CurrentFeedAt(now)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := titles(current.Channel.Items); got != "Episode 3, Episode 2" {
+		t.Errorf("expected %v got %v", "Episode 3, Episode 2", got)
+	}
+	archive, err := p.FeedArchiveAt(now, 1)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := titles(archive.Channel.Items); got != "Episode 1" {
+		t.Errorf("expected %v got %v", "Episode 1", got)
+	}
+}
+
+func TestFeedPageOrder(t *testing.T) {
+	// Items are split into pages by pubDate, and sorted in the Order of the podcast on each page.
+	p := setupPagedPodcast(5)
+	p.Order = EpisodeOrder
+	page, err := p.FeedPage(1)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := titles(page.Channel.Items); got != "Episode 4, Episode 5" {
+		t.Errorf("expected %v got %v", "Episode 4, Episode 5", got)
+	}
+	archive, err := p.FeedArchive(1)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := titles(archive.Channel.Items); got != "Episode 1, Episode 2" {
+		t.Errorf("expected %v got %v", "Episode 1, Episode 2", got)
+	}
+}
+
+func TestHistoryComplete(t *testing.T) {
+	feed, err := setupPagedPodcast(1).Feed(HistoryComplete)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	data, err := feed.XML()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !strings.Contains(data, "<fh:complete></fh:complete>") {
+		t.Errorf("expected %v to contain fh:complete", data)
+	}
+	parsed, err := Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if parsed.Channel.HistoryComplete == nil || parsed.Channel.HistoryArchive != nil {
+		t.Errorf("unexpected history flags %+v", parsed.Channel)
+	}
+
+	plain, _ := setupPagedPodcast(1).Feed()
+	if data, _ := plain.XML(); strings.Contains(data, "xmlns:fh") {
+		t.Errorf("expected %v not to declare fh namespace", data)
+	}
+}
+
+func TestPagingErrors(t *testing.T) {
+	p := setupPagedPodcast(3)
+	if _, err := p.FeedPage(0); err != ErrInvalidPage {
+		t.Errorf("expected %v got %v", ErrInvalidPage, err)
+	}
+	if _, err := p.FeedPage(3); err != ErrInvalidPage {
+		t.Errorf("expected %v got %v", ErrInvalidPage, err)
+	}
+	if _, err := p.FeedArchive(2); err != ErrInvalidPage {
+		t.Errorf("expected %v got %v", ErrInvalidPage, err)
+	}
+	if _, err := p.FeedPage(1, Type("invalid")); err != ErrInvalidShowType {
+		t.Errorf("expected %v got %v", ErrInvalidShowType, err)
+	}
+
+	unconfigured := &Podcast{}
+	if unconfigured.PageCount() != 1 || unconfigured.ArchiveCount() != 0 {
+		t.Errorf("unexpected counts %d %d", unconfigured.PageCount(), unconfigured.ArchiveCount())
+	}
+	if _, err := unconfigured.FeedPage(1); err != ErrInvalidPaging {
+		t.Errorf("expected %v got %v", ErrInvalidPaging, err)
+	}
+	if _, err := unconfigured.CurrentFeed(); err != ErrInvalidPaging {
+		t.Errorf("expected %v got %v", ErrInvalidPaging, err)
+	}
+	if _, err := unconfigured.FeedArchive(1); err != ErrInvalidPaging {
+		t.Errorf("expected %v got %v", ErrInvalidPaging, err)
+	}
+}
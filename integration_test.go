@@ -129,12 +129,14 @@ func TestCompleteIntegration(t *testing.T) {
 		t.Errorf("Parsed title doesn't match: expected %s, got %s", podcast.Title, parsedFeed.Channel.Title)
 	}
 
-	// Note: Items don't have explicit XML tags so they won't be parsed back
-	// But we can verify the XML contains the item content
+	// Verify the XML contains the item content and the items are parsed back
 	itemCount := strings.Count(xmlContent, "<item>")
 	if itemCount != 2 {
 		t.Errorf("Expected 2 items in XML, got %d", itemCount)
 	}
+	if len(parsedFeed.Channel.Items) != 2 {
+		t.Errorf("Expected 2 parsed items, got %d", len(parsedFeed.Channel.Items))
+	}
 }
 
 // TestXMLValidation tests that generated XML is well-formed
@@ -0,0 +1,264 @@
+package podcasts
+
+import (
+	"errors"
+	"mime"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidEpisode represents a error returned for invalid episode number.
+	ErrInvalidEpisode = errors.New("podcasts: invalid episode")
+
+	// ErrInvalidSeason represents a error returned for invalid season number.
+	ErrInvalidSeason = errors.New("podcasts: invalid season")
+
+	// ErrInvalidEpisodeType represents a error returned for invalid episode type.
+	ErrInvalidEpisodeType = errors.New("podcasts: invalid episode type")
+
+	// ErrInvalidMediaType represents a error returned for invalid media type of a linked file.
+	ErrInvalidMediaType = errors.New("podcasts: invalid media type")
+
+	// ErrInvalidLength represents a error returned for invalid enclosure length.
+	ErrInvalidLength = errors.New("podcasts: invalid length")
+)
+
+// NewItem returns a new item with given title and guid, and sets its options.
+func NewItem(title, guid string, options ...func(i *Item) error) (*Item, error) {
+	item := &Item{Title: title, GUID: guid}
+	if err := item.SetOptions(options...); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// SetOptions sets options of given item.
+func (i *Item) SetOptions(options ...func(i *Item) error) error {
+	for _, opt := range options {
+		if err := opt(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ItemPublished sets pubDate of given item.
+func ItemPublished(t time.Time) func(i *Item) error {
+	return func(i *Item) error {
+		i.PubDate = NewPubDate(t)
+		return nil
+	}
+}
+
+// ItemDescription sets description of given item, which may contain HTML.
+func ItemDescription(description string) func(i *Item) error {
+	return func(i *Item) error {
+		i.Description = &CDATAText{description}
+		return nil
+	}
+}
+
+// ItemContent sets content:encoded of given item, the full HTML show notes.
+func ItemContent(content string) func(i *Item) error {
+	return func(i *Item) error {
+		i.ContentEncoded = &CDATAText{content}
+		return nil
+	}
+}
+
+// ItunesTitle sets itunes:title of given item, the title without episode or season numbers.
+func ItunesTitle(title string) func(i *Item) error {
+	return func(i *Item) error {
+		i.ItunesTitle = title
+		return nil
+	}
+}
+
+// ItemAuthor sets itunes:author of given item.
+func ItemAuthor(author string) func(i *Item) error {
+	return func(i *Item) error {
+		i.Author = author
+		return nil
+	}
+}
+
+// ItemSubtitle sets itunes:subtitle of given item.
+func ItemSubtitle(subtitle string) func(i *Item) error {
+	return func(i *Item) error {
+		i.Subtitle = subtitle
+		return nil
+	}
+}
+
+// ItemSummary sets itunes:summary of given item.
+func ItemSummary(summary string) func(i *Item) error {
+	return func(i *Item) error {
+		i.Summary = &CDATAText{summary}
+		return nil
+	}
+}
+
+// ItemBlock enables itunes:block of given item.
+func ItemBlock(i *Item) error {
+	i.Block = ValueYes
+	return nil
+}
+
+// ItemExplicit enables itunes:explicit of given item.
+func ItemExplicit(i *Item) error {
+	i.Explicit = ValueYes
+	return nil
+}
+
+// ItemClean sets itunes:explicit of given item to false.
+func ItemClean(i *Item) error {
+	i.Explicit = "false"
+	return nil
+}
+
+// ItemClosedCaptioned enables itunes:isClosedCaptioned of given item.
+func ItemClosedCaptioned(i *Item) error {
+	i.ClosedCaptioned = ValueYes
+	return nil
+}
+
+// NotPermaLink marks the guid of given item as not being a permanent url.
+func NotPermaLink(i *Item) error {
+	i.GUIDIsPermaLink = "false"
+	return nil
+}
+
+// ItemDuration sets itunes:duration of given item.
+func ItemDuration(d time.Duration) func(i *Item) error {
+	return func(i *Item) error {
+		if d < 0 {
+			return ErrInvalidDuration
+		}
+		i.Duration = NewDuration(d)
+		return nil
+	}
+}
+
+// EpisodeNumber sets itunes:episode of given item, starting at 1.
+func EpisodeNumber(episode int) func(i *Item) error {
+	return func(i *Item) error {
+		if episode < 1 {
+			return ErrInvalidEpisode
+		}
+		i.Episode = episode
+		return nil
+	}
+}
+
+// SeasonNumber sets itunes:season of given item, starting at 1.
+func SeasonNumber(season int) func(i *Item) error {
+	return func(i *Item) error {
+		if season < 1 {
+			return ErrInvalidSeason
+		}
+		i.Season = season
+		return nil
+	}
+}
+
+// ItemEpisodeType sets itunes:episodeType of given item.
+func ItemEpisodeType(episodeType EpisodeType) func(i *Item) error {
+	return func(i *Item) error {
+		if episodeType != EpisodeFull && episodeType != EpisodeTrailer && episodeType != EpisodeBonus {
+			return ErrInvalidEpisodeType
+		}
+		i.EpisodeType = episodeType
+		return nil
+	}
+}
+
+// ItemImage sets itunes:image of given item.
+func ItemImage(href string) func(i *Item) error {
+	return func(i *Item) error {
+		if err := checkAbsoluteURL(href, ErrInvalidImage); err != nil {
+			return err
+		}
+		i.Image = &ItunesImage{Href: href}
+		return nil
+	}
+}
+
+// ItemEnclosure sets the enclosure of given item, with its length in bytes and media type.
+func ItemEnclosure(enclosureURL string, length int64, mediaType string) func(i *Item) error {
+	return func(i *Item) error {
+		if err := checkAbsoluteURL(enclosureURL, ErrInvalidURL); err != nil {
+			return err
+		}
+		if length <= 0 {
+			return ErrInvalidLength
+		}
+		if err := checkMediaType(mediaType); err != nil {
+			return err
+		}
+		i.Enclosure = &Enclosure{
+			URL:    enclosureURL,
+			Length: strconv.FormatInt(length, 10),
+			Type:   mediaType,
+		}
+		return nil
+	}
+}
+
+// Transcript adds a podcast:transcript of given item. Language is optional.
+func Transcript(transcriptURL, mediaType, language string) func(i *Item) error {
+	return func(i *Item) error {
+		if err := checkAbsoluteURL(transcriptURL, ErrInvalidURL); err != nil {
+			return err
+		}
+		if err := checkMediaType(mediaType); err != nil {
+			return err
+		}
+		i.Transcripts = append(i.Transcripts, &PodcastTranscript{
+			URL:      transcriptURL,
+			Type:     mediaType,
+			Language: language,
+		})
+		return nil
+	}
+}
+
+// Chapters sets podcast:chapters of given item.
+func Chapters(chaptersURL, mediaType string) func(i *Item) error {
+	return func(i *Item) error {
+		if err := checkAbsoluteURL(chaptersURL, ErrInvalidURL); err != nil {
+			return err
+		}
+		if err := checkMediaType(mediaType); err != nil {
+			return err
+		}
+		i.Chapters = &PodcastChapters{
+			URL:  chaptersURL,
+			Type: mediaType,
+		}
+		return nil
+	}
+}
+
+// checkAbsoluteURL returns the parsing error of rawURL, or invalid if it is not absolute.
+func checkAbsoluteURL(rawURL string, invalid error) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return invalid
+	}
+	return nil
+}
+
+// checkMediaType returns ErrInvalidMediaType unless mediaType is a type/subtype media type, with optional parameters.
+func checkMediaType(mediaType string) error {
+	parsed, _, err := mime.ParseMediaType(mediaType)
+	if err != nil || strings.Count(parsed, "/") != 1 || strings.HasPrefix(parsed, "/") || strings.HasSuffix(parsed, "/") {
+		return ErrInvalidMediaType
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package podcasts
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewItem(t *testing.T) {
+	published := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	item, err := NewItem("Episode 1", "https://example.com/1",
+		ItemPublished(published),
+		ItemDescription("<p>Notes</p>"),
+		ItemContent("<p>Full notes</p>"),
+		ItunesTitle("Pilot"),
+		ItemAuthor(testAuthor),
+		ItemSubtitle(testSubtitle),
+		ItemSummary("Summary"),
+		ItemBlock,
+		ItemExplicit,
+		ItemClosedCaptioned,
+		NotPermaLink,
+		ItemDuration(30*time.Minute),
+		EpisodeNumber(1),
+		SeasonNumber(2),
+		ItemEpisodeType(EpisodeTrailer),
+		ItemImage("https://example.com/1.jpg"),
+		ItemEnclosure("https://example.com/1.mp3", 1234, MediaTypeMP3),
+		Transcript("https://example.com/1.vtt", "text/vtt", "en"),
+		Transcript("https://example.com/1.srt", "application/x-subrip", ""),
+		Chapters("https://example.com/1.json", "application/json+chapters"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if item.Title != "Episode 1" || item.GUID != "https://example.com/1" || item.GUIDIsPermaLink != "false" {
+		t.Errorf("unexpected item %+v", item)
+	}
+	if !item.PubDate.Equal(published) || item.Duration.Duration != 30*time.Minute {
+		t.Errorf("unexpected item %+v", item)
+	}
+	if item.Description.Value != "<p>Notes</p>" || item.ContentEncoded.Value != "<p>Full notes</p>" || item.Summary.Value != "Summary" {
+		t.Errorf("unexpected item %+v", item)
+	}
+	if item.ItunesTitle != "Pilot" || item.Author != testAuthor || item.Subtitle != testSubtitle {
+		t.Errorf("unexpected item %+v", item)
+	}
+	if item.Block != ValueYes || item.Explicit != ValueYes || item.ClosedCaptioned != ValueYes {
+		t.Errorf("unexpected item %+v", item)
+	}
+	if item.Episode != 1 || item.Season != 2 || item.EpisodeType != EpisodeTrailer {
+		t.Errorf("unexpected item %+v", item)
+	}
+	if item.Image.Href != "https://example.com/1.jpg" {
+		t.Errorf("unexpected image %+v", item.Image)
+	}
+	want := Enclosure{URL: "https://example.com/1.mp3", Length: "1234", Type: MediaTypeMP3}
+	if *item.Enclosure != want {
+		t.Errorf("expected %+v got %+v", want, *item.Enclosure)
+	}
+	if len(item.Transcripts) != 2 || item.Transcripts[0].Language != "en" || item.Transcripts[1].Type != "application/x-subrip" {
+		t.Errorf("unexpected transcripts %+v", item.Transcripts)
+	}
+	if item.Chapters.URL != "https://example.com/1.json" {
+		t.Errorf("unexpected chapters %+v", item.Chapters)
+	}
+
+	if err := item.SetOptions(ItemClean); err != nil || item.Explicit != "false" {
+		t.Errorf("expected clean item got %v, %v", item.Explicit, err)
+	}
+
+	feed := &Feed{Channel: &Channel{Title: "Podcast", Explicit: "false", Items: []*Item{item}}}
+	for _, problem := range feed.Validate() {
+		if strings.HasPrefix(problem.Field, "Channel.Items") {
+			t.Errorf("unexpected validation problem %v", problem)
+		}
+	}
+}
+
+func TestItemOptionErrors(t *testing.T) {
+	cases := map[string]struct {
+		option func(i *Item) error
+		err    error
+	}{
+		"NegativeDuration":   {ItemDuration(-time.Second), ErrInvalidDuration},
+		"Episode":            {EpisodeNumber(0), ErrInvalidEpisode},
+		"Season":             {SeasonNumber(-1), ErrInvalidSeason},
+		"EpisodeType":        {ItemEpisodeType("special"), ErrInvalidEpisodeType},
+		"RelativeImage":      {ItemImage("/1.jpg"), ErrInvalidImage},
+		"RelativeEnclosure":  {ItemEnclosure("1.mp3", 1, MediaTypeMP3), ErrInvalidURL},
+		"EmptyEnclosure":     {ItemEnclosure("https://example.com/1.mp3", 0, MediaTypeMP3), ErrInvalidLength},
+		"EnclosureType":      {ItemEnclosure("https://example.com/1.mp3", 1, "MP3 audio"), ErrInvalidMediaType},
+		"RelativeTranscript": {Transcript("1.vtt", "text/vtt", ""), ErrInvalidURL},
+		"TranscriptType":     {Transcript("https://example.com/1.vtt", "", ""), ErrInvalidMediaType},
+		"RelativeChapters":   {Chapters("https://", "application/json+chapters"), ErrInvalidURL},
+		"ChaptersType":       {Chapters("https://example.com/1.json", "json"), ErrInvalidMediaType},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			item, err := NewItem("Episode", "guid", c.option)
+			if err != c.err {
+				t.Errorf("expected %v got %v", c.err, err)
+			}
+			if item != nil {
+				t.Errorf("expected no item got %+v", item)
+			}
+		})
+	}
+
+	if _, err := NewItem("Episode", "guid", ItemImage("%zz")); err == nil || errors.Is(err, ErrInvalidImage) {
+		t.Errorf("expected url parse error got %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+package podcasts
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+// jsonFeed represents a JSON Feed 1.1 document.
+type jsonFeed struct {
+	Version     string        `json:"version"`
+	Title       string        `json:"title"`
+	HomePageURL string        `json:"home_page_url,omitempty"`
+	FeedURL     string        `json:"feed_url,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Icon        string        `json:"icon,omitempty"`
+	Authors     []*jsonAuthor `json:"authors,omitempty"`
+	Language    string        `json:"language,omitempty"`
+	Expired     bool          `json:"expired,omitempty"`
+	Itunes      *jsonItunes   `json:"_itunes,omitempty"`
+	Items       []*jsonItem   `json:"items"`
+}
+
+// jsonAuthor represents an author of a JSON Feed or one of its items.
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+// jsonItem represents an item of a JSON Feed.
+type jsonItem struct {
+	ID            string            `json:"id"`
+	URL           string            `json:"url,omitempty"`
+	Title         string            `json:"title,omitempty"`
+	ContentHTML   string            `json:"content_html,omitempty"`
+	ContentText   string            `json:"content_text,omitempty"`
+	Summary       string            `json:"summary,omitempty"`
+	Image         string            `json:"image,omitempty"`
+	DatePublished string            `json:"date_published,omitempty"`
+	Authors       []*jsonAuthor     `json:"authors,omitempty"`
+	Attachments   []*jsonAttachment `json:"attachments,omitempty"`
+	Itunes        *jsonItunesItem   `json:"_itunes,omitempty"`
+}
+
+// jsonAttachment represents an attachment of a JSON Feed item.
+type jsonAttachment struct {
+	URL               string `json:"url"`
+	MIMEType          string `json:"mime_type"`
+	SizeInBytes       int64  `json:"size_in_bytes,omitempty"`
+	DurationInSeconds int64  `json:"duration_in_seconds,omitempty"`
+}
+
+// jsonItunes represents the _itunes extension of a JSON Feed.
+type jsonItunes struct {
+	Author     string                `json:"author,omitempty"`
+	Type       ShowType              `json:"type,omitempty"`
+	Block      string                `json:"block,omitempty"`
+	Explicit   string                `json:"explicit,omitempty"`
+	Complete   string                `json:"complete,omitempty"`
+	NewFeedURL string                `json:"new_feed_url,omitempty"`
+	Subtitle   string                `json:"subtitle,omitempty"`
+	Summary    string                `json:"summary,omitempty"`
+	Owner      *jsonItunesOwner      `json:"owner,omitempty"`
+	Categories []*jsonItunesCategory `json:"categories,omitempty"`
+}
+
+// jsonItunesOwner represents the owner in the _itunes extension of a JSON Feed.
+type jsonItunesOwner struct {
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+// jsonItunesCategory represents a category in the _itunes extension of a JSON Feed.
+type jsonItunesCategory struct {
+	Text       string                `json:"text"`
+	Categories []*jsonItunesCategory `json:"categories,omitempty"`
+}
+
+// jsonItunesItem represents the _itunes extension of a JSON Feed item.
+type jsonItunesItem struct {
+	Title           string      `json:"title,omitempty"`
+	Season          int         `json:"season,omitempty"`
+	Episode         int         `json:"episode,omitempty"`
+	EpisodeType     EpisodeType `json:"episode_type,omitempty"`
+	Block           string      `json:"block,omitempty"`
+	Explicit        string      `json:"explicit,omitempty"`
+	ClosedCaptioned string      `json:"closed_captioned,omitempty"`
+	Order           int         `json:"order,omitempty"`
+	Subtitle        string      `json:"subtitle,omitempty"`
+}
+
+// JSONFeed marshalls feed to JSON Feed 1.1 string.
+func (f *Feed) JSONFeed() (string, error) {
+	var buf bytes.Buffer
+	if err := f.WriteJSONFeed(&buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// WriteJSONFeed writes the feed as JSON Feed 1.1 to the given writer.
+// Podcast specific fields are written in the _itunes extension.
+func (f *Feed) WriteJSONFeed(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(newJSONFeed(f.Channel))
+}
+
+// newJSONFeed maps the channel to a JSON Feed.
+func newJSONFeed(c *Channel) *jsonFeed {
+	if c == nil {
+		c = &Channel{}
+	}
+	feed := &jsonFeed{
+		Version:     jsonFeedVersion,
+		Title:       c.Title,
+		HomePageURL: c.Link,
+		Description: c.Description,
+		Language:    c.Language,
+		Expired:     c.Complete == ValueYes,
+		Authors:     jsonAuthors(c.Author),
+		Items:       make([]*jsonItem, 0, len(c.Items)),
+	}
+	for _, link := range c.AtomLinks {
+		if link != nil && link.Rel == "self" {
+			feed.FeedURL = link.Href
+		}
+	}
+	if c.Image != nil {
+		feed.Icon = c.Image.Href
+	}
+	feed.Itunes = &jsonItunes{
+		Author:     c.Author,
+		Type:       c.Type,
+		Block:      c.Block,
+		Explicit:   c.Explicit,
+		Complete:   c.Complete,
+		NewFeedURL: c.NewFeedURL,
+		Subtitle:   c.Subtitle,
+		Summary:    cdataValue(c.Summary),
+		Categories: jsonCategories(c.Categories),
+	}
+	if c.Owner != nil {
+		feed.Itunes.Owner = &jsonItunesOwner{Name: c.Owner.Name, Email: c.Owner.Email}
+	}
+	if reflect.ValueOf(*feed.Itunes).IsZero() {
+		feed.Itunes = nil
+	}
+	feedID := feed.FeedURL
+	if feedID == "" {
+		feedID = feed.HomePageURL
+	}
+	for _, item := range c.Items {
+		if item != nil {
+			feed.Items = append(feed.Items, newJSONItem(feedID, item))
+		}
+	}
+	return feed
+}
+
+// newJSONItem maps the item to an item of the JSON Feed with given url.
+func newJSONItem(feedID string, item *Item) *jsonItem {
+	ji := &jsonItem{
+		ID:          item.GUID,
+		Title:       item.Title,
+		ContentHTML: cdataValue(item.ContentEncoded),
+		Summary:     item.Subtitle,
+		Authors:     jsonAuthors(item.Author),
+		Itunes: &jsonItunesItem{
+			Title:           item.ItunesTitle,
+			Season:          item.Season,
+			Episode:         item.Episode,
+			EpisodeType:     item.EpisodeType,
+			Block:           item.Block,
+			Explicit:        item.Explicit,
+			ClosedCaptioned: item.ClosedCaptioned,
+			Order:           item.Order,
+			Subtitle:        item.Subtitle,
+		},
+	}
+	if ji.ContentHTML == "" {
+		ji.ContentHTML = cdataValue(item.Description)
+	}
+	if ji.ContentHTML == "" {
+		ji.ContentText = cdataValue(item.Summary)
+	}
+	if ji.ContentHTML == "" && ji.ContentText == "" {
+		// JSON Feed requires either content_html or content_text.
+		ji.ContentText = item.Title
+	}
+	if ji.Summary == "" {
+		ji.Summary = cdataValue(item.Summary)
+	}
+	if item.GUIDIsPermaLink != "false" && isWebURL(item.GUID) {
+		ji.URL = item.GUID
+	}
+	if item.Image != nil {
+		ji.Image = item.Image.Href
+	}
+	if item.PubDate != nil {
+		ji.DatePublished = item.PubDate.Format(time.RFC3339)
+	}
+	if item.Enclosure != nil {
+		attachment := &jsonAttachment{
+			URL:      item.Enclosure.URL,
+			MIMEType: item.Enclosure.Type,
+		}
+		attachment.SizeInBytes, _ = strconv.ParseInt(item.Enclosure.Length, 10, 64)
+		if item.Duration != nil {
+			attachment.DurationInSeconds = int64(item.Duration.Seconds())
+		}
+		ji.Attachments = []*jsonAttachment{attachment}
+		if ji.ID == "" {
+			ji.ID = item.Enclosure.URL
+		}
+	}
+	if ji.ID == "" {
+		// JSON Feed requires an id, derived from the title when the item has no guid or enclosure.
+		ji.ID = nameUUID(feedID, item.Title)
+	}
+	if *ji.Itunes == (jsonItunesItem{}) {
+		ji.Itunes = nil
+	}
+	return ji
+}
+
+func jsonAuthors(name string) []*jsonAuthor {
+	if name == "" {
+		return nil
+	}
+	return []*jsonAuthor{{Name: name}}
+}
+
+func jsonCategories(categories []*ItunesCategory) []*jsonItunesCategory {
+	var mapped []*jsonItunesCategory
+	for _, category := range categories {
+		if category != nil {
+			mapped = append(mapped, &jsonItunesCategory{
+				Text:       category.Text,
+				Categories: jsonCategories(category.Categories),
+			})
+		}
+	}
+	return mapped
+}
+
+// cdataValue returns the value of text, or an empty string if text is nil.
+func cdataValue(text *CDATAText) string {
+	if text == nil {
+		return ""
+	}
+	return text.Value
+}
+
+// isWebURL reports whether s is an absolute http or https URL.
+func isWebURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
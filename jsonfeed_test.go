@@ -0,0 +1,165 @@
+package podcasts
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteJSONFeed(t *testing.T) {
+	podcast := &Podcast{
+		Title:       "JSON Podcast",
+		Description: "A podcast in JSON",
+		Link:        "https://example.com",
+		Language:    "en",
+	}
+	podcast.AddItem(&Item{
+		Title:          "Episode 1",
+		GUID:           "https://example.com/1",
+		PubDate:        NewPubDate(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)),
+		Duration:       NewDuration(time.Minute*30 + time.Second*5),
+		ContentEncoded: &CDATAText{Value: "<p>Show notes & links</p>"},
+		Subtitle:       "The first one",
+		Season:         1,
+		Episode:        1,
+		EpisodeType:    EpisodeFull,
+		Image:          &ItunesImage{Href: "https://example.com/1.jpg"},
+		Enclosure: &Enclosure{
+			URL:    "https://example.com/1.mp3",
+			Length: "14567890",
+			Type:   "audio/mpeg",
+		},
+	})
+	podcast.AddItem(&Item{
+		Title:           "Episode 2",
+		GUID:            "episode-2",
+		GUIDIsPermaLink: "false",
+		Description:     &CDATAText{Value: "Short description"},
+	})
+	podcast.AddItem(&Item{Title: "Episode 3", GUID: "https://example.com/3", GUIDIsPermaLink: "false"})
+
+	feed, err := podcast.Feed(
+		Author(testAuthor),
+		Explicit,
+		Complete,
+		Type(Serial),
+		Owner("Owner", "owner@example.com"),
+		Image("https://example.com/artwork.jpg"),
+		SelfLink("https://example.com/feed.xml"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	feed.Channel.Categories = []*ItunesCategory{{Text: "Technology"}}
+
+	data, err := feed.JSONFeed()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !strings.Contains(data, `"content_html": "<p>Show notes & links</p>"`) {
+		t.Errorf("expected %v to contain unescaped HTML content", data)
+	}
+
+	var got jsonFeed
+	if err := json.Unmarshal([]byte(data), &got); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got.Version != jsonFeedVersion || got.Title != podcast.Title || got.HomePageURL != podcast.Link {
+		t.Errorf("unexpected feed %+v", got)
+	}
+	if got.FeedURL != "https://example.com/feed.xml" || got.Icon != "https://example.com/artwork.jpg" {
+		t.Errorf("unexpected feed links %+v", got)
+	}
+	if !got.Expired || len(got.Authors) != 1 || got.Authors[0].Name != testAuthor {
+		t.Errorf("unexpected feed %+v", got)
+	}
+	if got.Itunes.Type != Serial || got.Itunes.Explicit != ValueYes || got.Itunes.Owner.Email != "owner@example.com" {
+		t.Errorf("unexpected _itunes %+v", got.Itunes)
+	}
+	if len(got.Itunes.Categories) != 1 || got.Itunes.Categories[0].Text != "Technology" {
+		t.Errorf("unexpected categories %+v", got.Itunes.Categories)
+	}
+	if len(got.Items) != 3 {
+		t.Fatalf("expected 3 items got %d", len(got.Items))
+	}
+
+	first := got.Items[0]
+	if first.ID != "https://example.com/1" || first.URL != "https://example.com/1" {
+		t.Errorf("unexpected item %+v", first)
+	}
+	if first.DatePublished != "2024-01-01T12:00:00Z" || first.Summary != "The first one" || first.Image != "https://example.com/1.jpg" {
+		t.Errorf("unexpected item %+v", first)
+	}
+	if first.Itunes.Season != 1 || first.Itunes.Episode != 1 || first.Itunes.EpisodeType != EpisodeFull {
+		t.Errorf("unexpected item _itunes %+v", first.Itunes)
+	}
+	want := jsonAttachment{URL: "https://example.com/1.mp3", MIMEType: "audio/mpeg", SizeInBytes: 14567890, DurationInSeconds: 1805}
+	if len(first.Attachments) != 1 || *first.Attachments[0] != want {
+		t.Errorf("expected %+v got %+v", want, first.Attachments)
+	}
+
+	second := got.Items[1]
+	if second.URL != "" || second.ContentHTML != "Short description" {
+		t.Errorf("unexpected item %+v", second)
+	}
+	third := got.Items[2]
+	if third.URL != "" || third.ContentText != "Episode 3" {
+		t.Errorf("unexpected item %+v", third)
+	}
+}
+
+func TestWriteJSONFeedEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&Feed{}).WriteJSONFeed(&buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if items, ok := got["items"].([]interface{}); !ok || len(items) != 0 {
+		t.Errorf("expected empty items array got %v", got["items"])
+	}
+}
+
+func TestWriteJSONFeedItemIDs(t *testing.T) {
+	feed := &Feed{Channel: &Channel{
+		Title: "IDs",
+		Items: []*Item{{Title: "Episode 1"}, {Title: "Episode 2"}},
+	}}
+	var buf bytes.Buffer
+	if err := feed.WriteJSONFeed(&buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, ok := got["_itunes"]; ok {
+		t.Errorf("expected no _itunes got %v", got["_itunes"])
+	}
+	items, _ := got["items"].([]interface{})
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items got %v", got["items"])
+	}
+	ids := make(map[string]bool)
+	for _, raw := range items {
+		item, _ := raw.(map[string]interface{})
+		id, _ := item["id"].(string)
+		if !strings.HasPrefix(id, "urn:uuid:") || ids[id] {
+			t.Errorf("expected unique urn:uuid id got %v", id)
+		}
+		ids[id] = true
+		if _, ok := item["_itunes"]; ok {
+			t.Errorf("expected no _itunes got %v", item["_itunes"])
+		}
+	}
+}
+
+func TestWriteJSONFeedError(t *testing.T) {
+	if err := (&Feed{}).WriteJSONFeed(&failingWriter{}); err == nil {
+		t.Error("expected error from failing writer")
+	}
+}
@@ -0,0 +1,426 @@
+package podcasts
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	headingLine   = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	listItemLine  = regexp.MustCompile(`^ {0,3}([-*+]|[0-9]{1,9}[.)])(?:[ \t]+(.*))?$`)
+	ruleLine      = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	setextLine    = regexp.MustCompile(`^ {0,3}(?:=+|-+)[ \t]*$`)
+	fenceLine     = regexp.MustCompile("^ {0,3}(```+|~~~+)")
+	quoteMarker   = regexp.MustCompile(`^ {0,3}> ?`)
+	entityPattern = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[a-zA-Z][a-zA-Z0-9]{1,31});`)
+)
+
+// ShowNotes sets the content:encoded of given item to the HTML rendered from
+// markdown, and its description and itunes:summary to the plain text of the
+// same notes, shortened to the 4000 characters shown by Apple Podcasts.
+func ShowNotes(markdown string) func(i *Item) error {
+	return func(i *Item) error {
+		content := RenderMarkdown(markdown)
+		text := truncateText(HTMLText(content), maxDescriptionLength)
+		i.ContentEncoded = &CDATAText{content}
+		i.Description = &CDATAText{text}
+		i.Summary = &CDATAText{text}
+		return nil
+	}
+}
+
+// RenderMarkdown renders Markdown show notes to the HTML subset kept by
+// SanitizeHTML. Headings are written as bold paragraphs, code as plain text,
+// images as their alternative text, and HTML in the Markdown as text. Bare
+// http and https urls become links.
+func RenderMarkdown(markdown string) string {
+	r := &markdownRenderer{}
+	lines := strings.Split(strings.ReplaceAll(strings.ReplaceAll(markdown, "\r\n", "\n"), "\r", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		i = r.renderLine(lines, i)
+	}
+	r.endBlock()
+	return strings.TrimSuffix(r.b.String(), "\n")
+}
+
+// markdownRenderer holds the state of the blocks being rendered.
+type markdownRenderer struct {
+	b strings.Builder
+	// paragraph holds the lines of the current paragraph or list item.
+	paragraph []string
+	// list is the tag of the open list, ul or ol, if any.
+	list   string
+	blank  bool
+	quoted bool
+}
+
+// renderLine renders line i of lines, returning the index of the last line
+// it used, which is further than i for code blocks.
+func (r *markdownRenderer) renderLine(lines []string, i int) int {
+	raw := r.unquote(lines[i])
+	line := strings.TrimRight(raw, " \t")
+	if strings.TrimSpace(line) == "" {
+		r.endParagraph()
+		r.blank = true
+		return i
+	}
+	switch {
+	case fenceLine.MatchString(line):
+		i = r.codeBlock(lines, i, fenceLine.FindStringSubmatch(line)[1])
+	case len(r.paragraph) > 0 && r.list == "" && setextLine.MatchString(line):
+		heading := strings.Join(r.paragraph, " ")
+		r.paragraph = nil
+		r.heading(heading)
+	case ruleLine.MatchString(line):
+		r.endBlock()
+	case headingLine.MatchString(line):
+		r.endBlock()
+		if heading := headingLine.FindStringSubmatch(line)[2]; heading != "" {
+			r.heading(heading)
+		}
+	case listItemLine.MatchString(raw):
+		r.listItem(listItemLine.FindStringSubmatch(raw))
+	case r.list != "" && r.blank && !isIndented(raw):
+		// An unindented paragraph after a blank line ends the list.
+		r.endBlock()
+		r.paragraph = []string{strings.TrimLeft(raw, " \t")}
+	default:
+		r.paragraph = append(r.paragraph, strings.TrimLeft(raw, " \t"))
+	}
+	r.blank = false
+	return i
+}
+
+// unquote returns line without its block quote markers, ending the current
+// block when the line enters or leaves a block quote.
+func (r *markdownRenderer) unquote(line string) string {
+	quoted := false
+	for quoteMarker.MatchString(line) {
+		// Block quotes have no tag of their own and are written as paragraphs.
+		line, quoted = quoteMarker.ReplaceAllString(line, ""), true
+	}
+	if quoted != r.quoted {
+		r.endBlock()
+		r.quoted = quoted
+	}
+	return line
+}
+
+// codeBlock writes the code block opened with fence at line i as plain text,
+// returning the index of its closing fence.
+func (r *markdownRenderer) codeBlock(lines []string, i int, fence string) int {
+	r.endBlock()
+	var code []string
+	for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+		code = append(code, textEscaper.Replace(lines[i]))
+	}
+	if text := strings.Join(code, "<br>\n"); text != "" {
+		r.b.WriteString("<p>" + text + "</p>\n")
+	}
+	return i
+}
+
+// heading writes a heading as a bold paragraph.
+func (r *markdownRenderer) heading(heading string) {
+	r.b.WriteString("<p><b>" + renderInline(heading) + "</b></p>\n")
+}
+
+// listItem starts the list item of given listItemLine match, opening its list if needed.
+func (r *markdownRenderer) listItem(match []string) {
+	list := "ul"
+	if marker := match[1]; marker[0] >= '0' && marker[0] <= '9' {
+		list = "ol"
+	}
+	if r.list != list {
+		r.endBlock()
+		r.list = list
+		r.b.WriteString("<" + list + ">\n")
+	} else {
+		r.endParagraph()
+	}
+	r.paragraph = []string{match[2]}
+}
+
+// endParagraph writes the current paragraph or list item.
+func (r *markdownRenderer) endParagraph() {
+	if len(r.paragraph) == 0 {
+		return
+	}
+	var b strings.Builder
+	for n, line := range r.paragraph {
+		last := n == len(r.paragraph)-1
+		switch {
+		case !last && strings.HasSuffix(line, "\\"):
+			b.WriteString(renderInline(strings.TrimSuffix(line, "\\")) + "<br>\n")
+		case !last && strings.HasSuffix(line, "  "):
+			b.WriteString(renderInline(strings.TrimRight(line, " ")) + "<br>\n")
+		case !last:
+			b.WriteString(renderInline(strings.TrimRight(line, " ")) + "\n")
+		default:
+			b.WriteString(renderInline(strings.TrimRight(line, " ")))
+		}
+	}
+	switch {
+	case b.Len() == 0:
+		// Empty items and paragraphs are left out, as SanitizeHTML does.
+	case r.list != "":
+		r.b.WriteString("<li>" + b.String() + "</li>\n")
+	default:
+		r.b.WriteString("<p>" + b.String() + "</p>\n")
+	}
+	r.paragraph = nil
+}
+
+// endBlock writes the current paragraph and closes the open list.
+func (r *markdownRenderer) endBlock() {
+	r.endParagraph()
+	if r.list != "" {
+		r.b.WriteString("</" + r.list + ">\n")
+		r.list = ""
+	}
+}
+
+// renderInline renders emphasis, links and code spans of a paragraph.
+func renderInline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if rendered, n := renderInlineAt(s, i); n > 0 {
+			b.WriteString(rendered)
+			i += n
+			continue
+		}
+		b.WriteString(textEscaper.Replace(s[i : i+1]))
+		i++
+	}
+	return b.String()
+}
+
+// renderInlineAt renders the inline element starting at s[i], returning its
+// length or 0 if there is none.
+func renderInlineAt(s string, i int) (string, int) {
+	switch c := s[i]; {
+	case c == '\\':
+		return renderEscape(s[i:])
+	case c == '`':
+		return renderCodeSpan(s[i:])
+	case c == '*' || c == '_':
+		if emphasis, n := renderEmphasis(s, i); n > 0 {
+			return emphasis, n
+		}
+		// Delimiters that do not open emphasis are text.
+		n := runLength(s[i:], c)
+		return s[i : i+n], n
+	case c == '!':
+		return renderImage(s[i:])
+	case c == '[':
+		return renderLink(s[i:])
+	case c == '<':
+		return renderAutolink(s[i:])
+	case c == 'h' && (i == 0 || !isWordByte(s[i-1])):
+		return renderBareURL(s[i:])
+	case c == '&':
+		return renderEntity(s[i:])
+	}
+	return "", 0
+}
+
+// renderEscape renders a backslash escaped punctuation character.
+func renderEscape(s string) (string, int) {
+	if len(s) > 1 && strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", s[1]) >= 0 {
+		return textEscaper.Replace(s[1:2]), 2
+	}
+	return "", 0
+}
+
+// renderCodeSpan renders a code span as plain text. Backticks that are not
+// closed are text.
+func renderCodeSpan(s string) (string, int) {
+	n := runLength(s, '`')
+	delimiter := s[:n]
+	if end := strings.Index(s[n:], delimiter); end >= 0 {
+		return textEscaper.Replace(strings.TrimSpace(s[n : n+end])), n + end + n
+	}
+	return delimiter, n
+}
+
+// renderImage renders an image as its alternative text.
+func renderImage(s string) (string, int) {
+	if !strings.HasPrefix(s[1:], "[") {
+		return "", 0
+	}
+	if text, _, n := parseLink(s[1:]); n > 0 {
+		return renderInline(text), 1 + n
+	}
+	return "", 0
+}
+
+// renderLink renders a link, or only its text if its href is not safe.
+func renderLink(s string) (string, int) {
+	text, href, n := parseLink(s)
+	if n == 0 {
+		return "", 0
+	}
+	if safe, ok := safeHref(href); ok {
+		return `<a href="` + attrEscaper.Replace(safe) + `">` + renderInline(text) + "</a>", n
+	}
+	return renderInline(text), n
+}
+
+// renderAutolink renders an autolink, such as <https://example.com>.
+func renderAutolink(s string) (string, int) {
+	end := strings.IndexByte(s, '>')
+	if end <= 0 {
+		return "", 0
+	}
+	if href, ok := safeHref(s[1:end]); ok && !strings.ContainsAny(href, " \t") {
+		return `<a href="` + attrEscaper.Replace(href) + `">` + textEscaper.Replace(href) + "</a>", end + 1
+	}
+	return "", 0
+}
+
+// renderBareURL renders a bare http or https url as a link, leaving out
+// trailing punctuation.
+func renderBareURL(s string) (string, int) {
+	if !strings.HasPrefix(s, "http://") && !strings.HasPrefix(s, "https://") {
+		return "", 0
+	}
+	end := strings.IndexAny(s+" ", " \t\n<")
+	href := strings.TrimRight(s[:end], ".,;:!?)\"'")
+	if safe, ok := safeHref(href); ok {
+		return `<a href="` + attrEscaper.Replace(safe) + `">` + textEscaper.Replace(safe) + "</a>", len(href)
+	}
+	return "", 0
+}
+
+// renderEntity renders an HTML character reference as the character.
+func renderEntity(s string) (string, int) {
+	if entity := entityPattern.FindString(s); entity != "" {
+		if decoded := html.UnescapeString(entity); decoded != entity {
+			return textEscaper.Replace(decoded), len(entity)
+		}
+	}
+	return "", 0
+}
+
+// renderEmphasis renders the emphasis starting at s[i], ** or __ as b and * or
+// _ as i, returning its length or 0 if it is not closed.
+func renderEmphasis(s string, i int) (string, int) {
+	c := s[i]
+	n := runLength(s[i:], c)
+	if n > 2 {
+		n = 2
+	}
+	open := i + n
+	if !opensEmphasis(s, i, open) {
+		return "", 0
+	}
+	for j := open + 1; j < len(s); j++ {
+		if s[j] != c {
+			continue
+		}
+		// Delimiters close emphasis of the same length, e.g. *a **b** c*.
+		if run := runLength(s[j:], c); run != n || !closesEmphasis(s, j, n) {
+			j += run - 1
+			continue
+		}
+		return emphasisTag(renderInline(s[open:j]), n), j + n - i
+	}
+	return "", 0
+}
+
+// opensEmphasis reports whether the delimiters from s[i] to s[open] open
+// emphasis. Opening delimiters are followed by text, and underscores cannot be
+// inside words.
+func opensEmphasis(s string, i, open int) bool {
+	return open < len(s) && s[open] != ' ' && s[open] != '\t' && !(s[i] == '_' && i > 0 && isWordByte(s[i-1]))
+}
+
+// closesEmphasis reports whether the n delimiters at s[j] close emphasis.
+func closesEmphasis(s string, j, n int) bool {
+	return s[j-1] != ' ' && s[j-1] != '\t' && s[j-1] != '\\' && !(s[j] == '_' && j+n < len(s) && isWordByte(s[j+n]))
+}
+
+// emphasisTag wraps inner in b for n of 2 delimiters and in i for 1. Empty
+// emphasis is left out.
+func emphasisTag(inner string, n int) string {
+	if inner == "" {
+		return ""
+	}
+	tag := "i"
+	if n == 2 {
+		tag = "b"
+	}
+	return "<" + tag + ">" + inner + "</" + tag + ">"
+}
+
+// parseLink parses a [text](href) link at the start of s, returning its
+// length or 0 if s does not start with a link.
+func parseLink(s string) (string, string, int) {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth > 0 {
+				continue
+			}
+			if i+1 >= len(s) || s[i+1] != '(' {
+				return "", "", 0
+			}
+			end := closingParen(s[i+2:])
+			if end < 0 {
+				return "", "", 0
+			}
+			target := strings.TrimSpace(s[i+2 : i+2+end])
+			// A title may follow the href, e.g. [a](https://example.com "title").
+			if space := strings.IndexAny(target, " \t"); space >= 0 {
+				target = target[:space]
+			}
+			target = strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+			return s[1:i], target, i + 2 + end + 1
+		}
+	}
+	return "", "", 0
+}
+
+// closingParen returns the index of the ) closing the link destination at the
+// start of s, skipping balanced parentheses, or -1.
+func closingParen(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+func runLength(s string, c byte) int {
+	n := 0
+	for n < len(s) && s[n] == c {
+		n++
+	}
+	return n
+}
+
+// isIndented reports whether line starts with two spaces or a tab.
+func isIndented(line string) bool {
+	return strings.HasPrefix(line, "  ") || strings.HasPrefix(line, "\t")
+}
+
+func isWordByte(c byte) bool {
+	return isASCIILetter(c) || c >= '0' && c <= '9' || c >= 0x80
+}
@@ -0,0 +1,134 @@
+package podcasts
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+const testShowNotes = `# Episode 42: *Go* & friends
+
+We talk about **generics**, ` + "`any`" + ` and [the spec](https://go.dev/ref/spec "Spec").
+Second line\
+Third line
+
+- Item one
+- Item _two_ with https://example.com/x.
+  continued
+
+1. first
+2) second
+
+After the list <script>alert(1)</script>
+
+> quoted
+> text
+
+---
+
+` + "```" + `
+code <x>
+` + "```" + `
+
+![logo](https://example.com/logo.png) [unsafe](javascript:alert(1)) <https://example.com/y> snake_case_name AT&amp;T
+`
+
+func TestRenderMarkdown(t *testing.T) {
+	want := `<p><b>Episode 42: <i>Go</i> &amp; friends</b></p>
+<p>We talk about <b>generics</b>, any and <a href="https://go.dev/ref/spec">the spec</a>.
+Second line<br>
+Third line</p>
+<ul>
+<li>Item one</li>
+<li>Item <i>two</i> with <a href="https://example.com/x">https://example.com/x</a>.
+continued</li>
+</ul>
+<ol>
+<li>first</li>
+<li>second</li>
+</ol>
+<p>After the list &lt;script&gt;alert(1)&lt;/script&gt;</p>
+<p>quoted
+text</p>
+<p>code &lt;x&gt;</p>
+<p>logo unsafe <a href="https://example.com/y">https://example.com/y</a> snake_case_name AT&amp;T</p>`
+	got := RenderMarkdown(testShowNotes)
+	if got != want {
+		t.Errorf("expected %v got %v", want, got)
+	}
+	if sanitized := SanitizeHTML(got); sanitized != got {
+		t.Errorf("expected rendered HTML to be sanitized, got %v", sanitized)
+	}
+}
+
+func TestRenderMarkdownInline(t *testing.T) {
+	cases := map[string]string{
+		"**bold** and __bold__": "<b>bold</b> and <b>bold</b>",
+		"*a **b** c*":           "<i>a <b>b</b> c</i>",
+		"2 * 3 * 4":             "2 * 3 * 4",
+		"\\*not emphasis\\*":    "*not emphasis*",
+		"unclosed **bold":       "unclosed **bold",
+		"[a [nested] link](https://example.com/a_(b))": `<a href="https://example.com/a_(b)">a [nested] link</a>`,
+		"(see https://example.com).":                   `(see <a href="https://example.com">https://example.com</a>).`,
+		"Setext\n======":                               "<b>Setext</b>",
+	}
+	for markdown, want := range cases {
+		got := strings.TrimSuffix(strings.TrimPrefix(RenderMarkdown(markdown), "<p>"), "</p>")
+		if got != want {
+			t.Errorf("%q: expected %v got %v", markdown, want, got)
+		}
+	}
+}
+
+func TestShowNotes(t *testing.T) {
+	item, err := NewItem("Episode 42", "https://example.com/42", ShowNotes(testShowNotes))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if item.ContentEncoded.Value != RenderMarkdown(testShowNotes) {
+		t.Errorf("expected %v got %v", RenderMarkdown(testShowNotes), item.ContentEncoded.Value)
+	}
+	text := item.Description.Value
+	for _, want := range []string{
+		"Episode 42: Go & friends\n\nWe talk about generics, any and the spec (https://go.dev/ref/spec). Second line\nThird line",
+		"- Item two with https://example.com/x. continued\n\n1. first\n2. second",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q to contain %q", text, want)
+		}
+	}
+	if item.Summary.Value != text {
+		t.Errorf("expected %v got %v", text, item.Summary.Value)
+	}
+
+	long, err := NewItem("Episode 43", "https://example.com/43", ShowNotes(strings.Repeat("Lorem ipsum dolor sit amet. ", 500)))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if n := utf8.RuneCountInString(long.Summary.Value); n > maxDescriptionLength {
+		t.Errorf("expected at most %d characters got %d", maxDescriptionLength, n)
+	}
+	if !strings.HasSuffix(long.Summary.Value, "…") {
+		t.Errorf("expected %v to be truncated", long.Summary.Value)
+	}
+	feed := &Feed{Channel: &Channel{Items: []*Item{long}}}
+	for _, problem := range feed.Validate() {
+		if strings.HasPrefix(problem.Field, "Channel.Items[0].") && strings.Contains(problem.Message, "longer than") {
+			t.Errorf("unexpected problem %v", problem)
+		}
+	}
+}
+
+func TestValidateDescriptionLength(t *testing.T) {
+	long := &CDATAText{strings.Repeat("a", maxDescriptionLength+1)}
+	feed := &Feed{Channel: &Channel{Items: []*Item{{Description: long, ContentEncoded: long}}}}
+	var fields []string
+	for _, problem := range feed.Validate() {
+		if strings.Contains(problem.Message, "longer than") {
+			fields = append(fields, problem.Field)
+		}
+	}
+	if got := strings.Join(fields, ", "); got != "Channel.Items[0].Description" {
+		t.Errorf("expected %v got %v", "Channel.Items[0].Description", got)
+	}
+}
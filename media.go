@@ -0,0 +1,131 @@
+package podcasts
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrUnknownMedia represents a error returned for media files of unsupported format.
+	ErrUnknownMedia = errors.New("podcasts: unknown media format")
+
+	// ErrInvalidMedia represents a error returned for truncated or corrupt media files.
+	ErrInvalidMedia = errors.New("podcasts: invalid media")
+)
+
+const (
+	// MediaTypeMP3 is the MIME type of MP3 audio.
+	MediaTypeMP3 = "audio/mpeg"
+	// MediaTypeM4A is the MIME type of MPEG-4 audio.
+	MediaTypeM4A = "audio/x-m4a"
+	// MediaTypeMP4 is the MIME type of MPEG-4 video.
+	MediaTypeMP4 = "video/mp4"
+	// MediaTypeOgg is the MIME type of Ogg Opus and Ogg Vorbis audio.
+	MediaTypeOgg = "audio/ogg"
+)
+
+// Media represents the metadata of a media file read by InspectMedia.
+type Media struct {
+	// Type is the MIME type of the file.
+	Type string
+	// Length is the size of the file in bytes.
+	Length int64
+	// Duration is the playing time of the file.
+	Duration time.Duration
+}
+
+// Enclosure returns the enclosure of the media served from given url.
+func (m *Media) Enclosure(mediaURL string) *Enclosure {
+	return &Enclosure{
+		URL:    mediaURL,
+		Length: strconv.FormatInt(m.Length, 10),
+		Type:   m.Type,
+	}
+}
+
+// InspectFile reads the media file at path and returns its enclosure, served
+// from given url, and its duration.
+func InspectFile(path, mediaURL string) (*Enclosure, *Duration, error) {
+	media, err := inspectFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return media.Enclosure(mediaURL), NewDuration(media.Duration), nil
+}
+
+func inspectFile(path string) (*Media, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	media, err := InspectMedia(file, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return media, nil
+}
+
+// InspectMedia reads the MIME type and duration of the media of given size.
+//
+// Supported formats are MP3, with the duration taken from the Xing or VBRI
+// header of VBR files or by counting frames otherwise, MPEG-4 audio and
+// video, from the mvhd atom, and Ogg Opus and Ogg Vorbis, from the granule
+// position of the last page.
+func InspectMedia(r io.ReaderAt, size int64) (*Media, error) {
+	if size < 12 {
+		return nil, ErrUnknownMedia
+	}
+	head, err := readAt(r, 0, 12)
+	if err != nil {
+		return nil, err
+	}
+	media := &Media{Length: size}
+	switch {
+	case string(head[4:8]) == "ftyp":
+		media.Type, media.Duration, err = inspectMP4(r, size)
+	case string(head[:4]) == "OggS":
+		media.Type = MediaTypeOgg
+		media.Duration, err = inspectOgg(r, size)
+	case string(head[:3]) == "ID3" || (head[0] == 0xFF && head[1]&0xE0 == 0xE0):
+		media.Type = MediaTypeMP3
+		media.Duration, err = inspectMP3(r, size)
+	default:
+		return nil, ErrUnknownMedia
+	}
+	if err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// readAt reads n bytes at offset off of r.
+func readAt(r io.ReaderAt, off int64, n int) ([]byte, error) {
+	b := make([]byte, n)
+	read, err := r.ReadAt(b, off)
+	if read == n {
+		return b, nil
+	}
+	if err == nil || err == io.EOF {
+		return nil, fmt.Errorf("%w: unexpected end of file", ErrInvalidMedia)
+	}
+	return nil, err
+}
+
+// samplesDuration returns the playing time of given number of samples.
+func samplesDuration(samples, sampleRate int64) time.Duration {
+	if samples <= 0 || sampleRate <= 0 {
+		return 0
+	}
+	seconds := samples / sampleRate
+	rest := samples % sampleRate
+	return time.Duration(seconds)*time.Second + time.Duration(rest)*time.Second/time.Duration(sampleRate)
+}
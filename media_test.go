@@ -0,0 +1,223 @@
+package podcasts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// MPEG 1 layer III frame header, 128 kbit/s, 44100 Hz, stereo: 417 byte frames of 1152 samples.
+var mpeg1Header = []byte{0xFF, 0xFB, 0x90, 0x00}
+
+// MPEG 2 layer III frame header, 64 kbit/s, 22050 Hz, mono: 208 byte frames of 576 samples.
+var mpeg2Header = []byte{0xFF, 0xF3, 0x80, 0xC0}
+
+func mp3Frames(header []byte, length, n int) []byte {
+	frame := make([]byte, length)
+	copy(frame, header)
+	return bytes.Repeat(frame, n)
+}
+
+func id3v2Tag(size int) []byte {
+	tag := []byte{'I', 'D', '3', 3, 0, 0, byte(size >> 21 & 0x7F), byte(size >> 14 & 0x7F), byte(size >> 7 & 0x7F), byte(size & 0x7F)}
+	return append(tag, make([]byte, size)...)
+}
+
+func mp4Box(boxType string, children ...[]byte) []byte {
+	body := bytes.Join(children, nil)
+	box := make([]byte, 8, 8+len(body))
+	binary.BigEndian.PutUint32(box, uint32(8+len(body)))
+	copy(box[4:], boxType)
+	return append(box, body...)
+}
+
+func mvhd(timescale, duration uint32) []byte {
+	body := make([]byte, 100)
+	binary.BigEndian.PutUint32(body[12:], timescale)
+	binary.BigEndian.PutUint32(body[16:], duration)
+	return mp4Box("mvhd", body)
+}
+
+func mvhdVersion1(timescale uint32, duration uint64) []byte {
+	body := make([]byte, 112)
+	body[0] = 1
+	binary.BigEndian.PutUint32(body[20:], timescale)
+	binary.BigEndian.PutUint64(body[24:], duration)
+	return mp4Box("mvhd", body)
+}
+
+func trak(handler string) []byte {
+	body := make([]byte, 25)
+	copy(body[8:], handler)
+	return mp4Box("trak", mp4Box("tkhd", make([]byte, 84)), mp4Box("mdia", mp4Box("hdlr", body)))
+}
+
+func oggPage(serial uint32, granule uint64, body []byte) []byte {
+	page := make([]byte, oggPageHeaderSize)
+	copy(page, "OggS")
+	binary.LittleEndian.PutUint64(page[6:], granule)
+	binary.LittleEndian.PutUint32(page[14:], serial)
+	n := len(body)
+	for ; n >= 255; n -= 255 {
+		page = append(page, 255)
+	}
+	page = append(page, byte(n))
+	page[26] = byte(len(page) - oggPageHeaderSize)
+	return append(page, body...)
+}
+
+func opusHead(preSkip uint16) []byte {
+	head := make([]byte, 19)
+	copy(head, "OpusHead")
+	head[8], head[9] = 1, 2
+	binary.LittleEndian.PutUint16(head[10:], preSkip)
+	binary.LittleEndian.PutUint32(head[12:], 44100)
+	return head
+}
+
+func vorbisHead(sampleRate uint32) []byte {
+	head := make([]byte, 30)
+	copy(head, "\x01vorbis")
+	head[11] = 2
+	binary.LittleEndian.PutUint32(head[12:], sampleRate)
+	return head
+}
+
+func TestInspectMedia(t *testing.T) {
+	xing := mp3Frames(mpeg1Header, 417, 1)
+	copy(xing[36:], "Xing\x00\x00\x00\x01\x00\x00\x03\xE8")
+	info := mp3Frames(mpeg1Header, 417, 1)
+	copy(info[36:], "Info\x00\x00\x00\x0F\x00\x00\x00\x64")
+	vbri := mp3Frames(mpeg1Header, 417, 1)
+	copy(vbri[36:], "VBRI\x00\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x07\xD0")
+	id3v1 := append([]byte("TAG"), make([]byte, 125)...)
+
+	audio := bytes.Repeat([]byte{0x55}, 1000)
+	otherStream := oggPage(2, 1<<40, []byte("other"))
+
+	cases := map[string]struct {
+		data     []byte
+		mimeType string
+		duration time.Duration
+	}{
+		"MP3CBR": {
+			mp3Frames(mpeg1Header, 417, 100),
+			MediaTypeMP3, samplesDuration(100*1152, 44100),
+		},
+		"MP3Tags": {
+			bytes.Join([][]byte{id3v2Tag(300), mp3Frames(mpeg1Header, 417, 50), id3v1}, nil),
+			MediaTypeMP3, samplesDuration(50*1152, 44100),
+		},
+		"MP3Garbage": {
+			bytes.Join([][]byte{id3v2Tag(20), {0x00, 0xFF, 0xFB, 0x00}, mp3Frames(mpeg2Header, 208, 10)}, nil),
+			MediaTypeMP3, samplesDuration(10*576, 22050),
+		},
+		"MP3Xing": {
+			bytes.Join([][]byte{xing, mp3Frames(mpeg1Header, 417, 5)}, nil),
+			MediaTypeMP3, samplesDuration(1000*1152, 44100),
+		},
+		"MP3Info": {
+			bytes.Join([][]byte{id3v2Tag(10), info, mp3Frames(mpeg1Header, 417, 5)}, nil),
+			MediaTypeMP3, samplesDuration(100*1152, 44100),
+		},
+		"MP3VBRI": {
+			bytes.Join([][]byte{vbri, mp3Frames(mpeg1Header, 417, 5)}, nil),
+			MediaTypeMP3, samplesDuration(2000*1152, 44100),
+		},
+		"M4A": {
+			bytes.Join([][]byte{
+				mp4Box("ftyp", []byte("M4A \x00\x00\x00\x00")),
+				mp4Box("mdat", audio),
+				mp4Box("moov", mvhd(44100, 44100*90+22050), trak("soun")),
+			}, nil),
+			MediaTypeM4A, 90*time.Second + 500*time.Millisecond,
+		},
+		"MP4Video": {
+			bytes.Join([][]byte{
+				mp4Box("ftyp", []byte("isom\x00\x00\x00\x00")),
+				mp4Box("moov", mvhdVersion1(1000, 61500), trak("soun"), trak("vide")),
+				mp4Box("mdat", audio),
+			}, nil),
+			MediaTypeMP4, 61500 * time.Millisecond,
+		},
+		"Opus": {
+			bytes.Join([][]byte{
+				oggPage(1, 0, opusHead(312)),
+				oggPage(1, 0, []byte("OpusTags")),
+				oggPage(1, 1<<64-1, audio),
+				oggPage(1, 48000*10+312, audio),
+				otherStream,
+			}, nil),
+			MediaTypeOgg, 10 * time.Second,
+		},
+		"Vorbis": {
+			bytes.Join([][]byte{
+				oggPage(1, 0, vorbisHead(44100)),
+				oggPage(1, 44100*5, audio),
+			}, nil),
+			MediaTypeOgg, 5 * time.Second,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			media, err := InspectMedia(bytes.NewReader(c.data), int64(len(c.data)))
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			want := Media{Type: c.mimeType, Length: int64(len(c.data)), Duration: c.duration}
+			if *media != want {
+				t.Errorf("expected %+v got %+v", want, *media)
+			}
+		})
+	}
+}
+
+func TestInspectMediaErrors(t *testing.T) {
+	cases := map[string]struct {
+		data []byte
+		want error
+	}{
+		"Empty":        {nil, ErrUnknownMedia},
+		"Text":         {[]byte("This is not a media file"), ErrUnknownMedia},
+		"MP3NoFrames":  {id3v2Tag(100), ErrInvalidMedia},
+		"MP3Truncated": {id3v2Tag(100)[:50], ErrInvalidMedia},
+		"MP4NoMoov":    {mp4Box("ftyp", []byte("M4A \x00\x00\x00\x00")), ErrInvalidMedia},
+		"MP4BadSize":   {append(mp4Box("ftyp", []byte("M4A \x00\x00\x00\x00")), 0, 0, 1, 0, 'm', 'o', 'o', 'v'), ErrInvalidMedia},
+		"OggFLAC":      {oggPage(1, 0, []byte("\x7FFLAC\x01\x00\x00\x00fLaC")), ErrUnknownMedia},
+		"OggNoGranule": {oggPage(1, 1<<64-1, opusHead(0)), ErrInvalidMedia},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := InspectMedia(bytes.NewReader(c.data), int64(len(c.data))); !errors.Is(err, c.want) {
+				t.Errorf("expected %v got %v", c.want, err)
+			}
+		})
+	}
+}
+
+func TestInspectFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "episode.mp3")
+	data := mp3Frames(mpeg1Header, 417, 2000)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	enclosure, duration, err := InspectFile(path, "https://example.com/episode.mp3")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := Enclosure{URL: "https://example.com/episode.mp3", Length: "834000", Type: MediaTypeMP3}
+	if *enclosure != want {
+		t.Errorf("expected %+v got %+v", want, *enclosure)
+	}
+	if duration.Duration != samplesDuration(2000*1152, 44100) {
+		t.Errorf("expected %v got %v", samplesDuration(2000*1152, 44100), duration.Duration)
+	}
+
+	if _, _, err := InspectFile(filepath.Join(t.TempDir(), "missing.mp3"), ""); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected %v got %v", os.ErrNotExist, err)
+	}
+}
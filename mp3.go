@@ -0,0 +1,238 @@
+package podcasts
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// mp3SearchLimit is how far into the audio the first MP3 frame is looked for.
+const mp3SearchLimit = 64 * 1024
+
+// mp3Bitrates lists the bitrates in kbit/s of MPEG 1 and MPEG 2/2.5 by layer and index.
+var mp3Bitrates = [2][3][15]int{
+	{
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448},
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+	},
+	{
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+	},
+}
+
+// mp3SampleRates lists the sample rates of MPEG 2.5, reserved, MPEG 2 and MPEG 1 by index.
+var mp3SampleRates = [4][3]int{
+	{11025, 12000, 8000},
+	{},
+	{22050, 24000, 16000},
+	{44100, 48000, 32000},
+}
+
+// mp3Frame represents the header of an MPEG audio frame.
+type mp3Frame struct {
+	mpeg1      bool
+	layer      int
+	sampleRate int
+	samples    int
+	length     int
+	sideInfo   int
+}
+
+// parseMP3Frame parses the 4 byte frame header at the start of b.
+func parseMP3Frame(b []byte) (mp3Frame, bool) {
+	if len(b) < 4 || b[0] != 0xFF || b[1]&0xE0 != 0xE0 {
+		return mp3Frame{}, false
+	}
+	version := b[1] >> 3 & 3
+	layer := 4 - int(b[1]>>1&3)
+	bitrateIndex := b[2] >> 4
+	sampleRateIndex := b[2] >> 2 & 3
+	if version == 1 || layer == 4 || bitrateIndex == 0 || bitrateIndex == 15 || sampleRateIndex == 3 {
+		// Reserved values, and free format streams whose frame length is unknown.
+		return mp3Frame{}, false
+	}
+	f := mp3Frame{
+		mpeg1:      version == 3,
+		layer:      layer,
+		sampleRate: mp3SampleRates[version][sampleRateIndex],
+	}
+	padding := int(b[2] >> 1 & 1)
+	f.samples, f.length = f.size(mp3Bitrate(f.mpeg1, layer, bitrateIndex), padding)
+	if layer == 3 {
+		f.sideInfo = mp3SideInfo(f.mpeg1, b[3]>>6 == 3)
+	}
+	return f, true
+}
+
+// mp3Bitrate returns the bitrate in bit/s of given bitrate index.
+func mp3Bitrate(mpeg1 bool, layer int, index byte) int {
+	table := 1
+	if mpeg1 {
+		table = 0
+	}
+	return mp3Bitrates[table][layer-1][index] * 1000
+}
+
+// size returns the number of samples and the length in bytes of the frame.
+func (f mp3Frame) size(bitrate, padding int) (int, int) {
+	switch {
+	case f.layer == 1:
+		return 384, (12*bitrate/f.sampleRate + padding) * 4
+	case f.layer == 2 || f.mpeg1:
+		return 1152, 144*bitrate/f.sampleRate + padding
+	default:
+		return 576, 72*bitrate/f.sampleRate + padding
+	}
+}
+
+// mp3SideInfo returns the size of the side information of a layer III frame,
+// which the Xing header follows.
+func mp3SideInfo(mpeg1, mono bool) int {
+	switch {
+	case mpeg1 && mono:
+		return 17
+	case mpeg1:
+		return 32
+	case mono:
+		return 9
+	default:
+		return 17
+	}
+}
+
+// inspectMP3 returns the duration of the MP3 audio of given size.
+func inspectMP3(r io.ReaderAt, size int64) (time.Duration, error) {
+	start, err := skipID3v2(r, size)
+	if err != nil {
+		return 0, err
+	}
+	end := size
+	if end-start >= 128 {
+		if tag, err := readAt(r, end-128, 3); err == nil && string(tag) == "TAG" {
+			end -= 128
+		}
+	}
+
+	headSize := end - start
+	if headSize > mp3SearchLimit {
+		headSize = mp3SearchLimit
+	}
+	head, err := readAt(r, start, int(headSize))
+	if err != nil {
+		return 0, err
+	}
+	offset := findMP3Frame(head)
+	if offset < 0 {
+		return 0, fmt.Errorf("%w: no MPEG audio frame found", ErrInvalidMedia)
+	}
+	first, _ := parseMP3Frame(head[offset:])
+	if frames, ok := mp3VBRFrames(head[offset:], first); ok {
+		return samplesDuration(frames*int64(first.samples), int64(first.sampleRate)), nil
+	}
+
+	// Without a VBR header the frames are counted, which is exact for both CBR and VBR files.
+	br := bufio.NewReaderSize(io.NewSectionReader(r, start+int64(offset), end-start-int64(offset)), 64*1024)
+	var samples int64
+	header := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(br, header); err != nil {
+			break
+		}
+		frame, ok := parseMP3Frame(header)
+		if !ok {
+			break
+		}
+		if _, err := br.Discard(frame.length - len(header)); err != nil {
+			break
+		}
+		samples += int64(frame.samples)
+	}
+	return samplesDuration(samples, int64(first.sampleRate)), nil
+}
+
+// skipID3v2 returns the offset of the audio following any ID3v2 tags.
+func skipID3v2(r io.ReaderAt, size int64) (int64, error) {
+	var offset int64
+	for offset+10 <= size {
+		header, err := readAt(r, offset, 10)
+		if err != nil {
+			return 0, err
+		}
+		if string(header[:3]) != "ID3" {
+			break
+		}
+		offset += 10 + syncsafe(header[6:10])
+		if header[5]&0x10 != 0 {
+			// Footer present.
+			offset += 10
+		}
+	}
+	if offset > size {
+		return 0, fmt.Errorf("%w: ID3v2 tag exceeds file", ErrInvalidMedia)
+	}
+	return offset, nil
+}
+
+// syncsafe decodes a 28 bit syncsafe integer, as used in ID3v2 headers.
+func syncsafe(b []byte) int64 {
+	return int64(b[0]&0x7F)<<21 | int64(b[1]&0x7F)<<14 | int64(b[2]&0x7F)<<7 | int64(b[3]&0x7F)
+}
+
+// findMP3Frame returns the offset of the first frame in b followed by another
+// frame, so that stray sync bits are not mistaken for audio, or -1.
+func findMP3Frame(b []byte) int {
+	for i := 0; i+4 <= len(b); i++ {
+		frame, ok := parseMP3Frame(b[i:])
+		if !ok {
+			continue
+		}
+		next := i + frame.length
+		if next+4 > len(b) {
+			return i
+		}
+		if _, ok := parseMP3Frame(b[next:]); ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// mp3VBRFrames returns the number of frames recorded in the Xing, Info or VBRI
+// header of the first frame f at the start of b.
+func mp3VBRFrames(b []byte, f mp3Frame) (int64, bool) {
+	if f.layer != 3 {
+		return 0, false
+	}
+	if frames, ok := xingFrames(b, 4+f.sideInfo); ok {
+		return frames, true
+	}
+	return vbriFrames(b)
+}
+
+// xingFrames returns the number of frames recorded in the Xing or Info header at offset of b.
+func xingFrames(b []byte, offset int) (int64, bool) {
+	if len(b) < offset+12 {
+		return 0, false
+	}
+	tag := string(b[offset : offset+4])
+	flags := binary.BigEndian.Uint32(b[offset+4:])
+	if (tag == "Xing" || tag == "Info") && flags&1 != 0 {
+		return int64(binary.BigEndian.Uint32(b[offset+8:])), true
+	}
+	return 0, false
+}
+
+// vbriFrames returns the number of frames recorded in the VBRI header, which
+// always follows 32 bytes after the frame header.
+func vbriFrames(b []byte) (int64, bool) {
+	const vbri = 4 + 32
+	if len(b) >= vbri+18 && string(b[vbri:vbri+4]) == "VBRI" {
+		return int64(binary.BigEndian.Uint32(b[vbri+14:])), true
+	}
+	return 0, false
+}
@@ -0,0 +1,138 @@
+package podcasts
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// errStopWalk stops walkMP4 without an error.
+var errStopWalk = errors.New("stop walk")
+
+// walkMP4 calls fn for each box between start and end, with the offsets of its body.
+func walkMP4(r io.ReaderAt, start, end int64, fn func(boxType string, body, bodyEnd int64) error) error {
+	for offset := start; offset+8 <= end; {
+		header, err := readAt(r, offset, 8)
+		if err != nil {
+			return err
+		}
+		size := int64(binary.BigEndian.Uint32(header))
+		boxType := string(header[4:8])
+		headerSize := int64(8)
+		switch size {
+		case 0:
+			// The box extends to the end of the file.
+			size = end - offset
+		case 1:
+			large, err := readAt(r, offset+8, 8)
+			if err != nil {
+				return err
+			}
+			size = int64(binary.BigEndian.Uint64(large))
+			headerSize = 16
+		}
+		if size < headerSize || size > end-offset {
+			return fmt.Errorf("%w: invalid size of %q box", ErrInvalidMedia, boxType)
+		}
+		if err := fn(boxType, offset+headerSize, offset+size); err != nil {
+			return err
+		}
+		offset += size
+	}
+	return nil
+}
+
+// inspectMP4 returns the MIME type and duration of the MPEG-4 file of given size.
+// The duration is read from the mvhd atom and files with a video track are reported as video.
+func inspectMP4(r io.ReaderAt, size int64) (string, time.Duration, error) {
+	var duration time.Duration
+	moov, video := false, false
+	err := walkMP4(r, 0, size, func(boxType string, body, bodyEnd int64) error {
+		if boxType != "moov" {
+			return nil
+		}
+		moov = true
+		return walkMP4(r, body, bodyEnd, func(boxType string, body, bodyEnd int64) error {
+			switch boxType {
+			case "mvhd":
+				d, err := mp4Duration(r, body)
+				duration = d
+				return err
+			case "trak":
+				isVideo, err := mp4IsVideo(r, body, bodyEnd)
+				video = video || isVideo
+				return err
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	if !moov {
+		return "", 0, fmt.Errorf("%w: moov box not found", ErrInvalidMedia)
+	}
+	if video {
+		return MediaTypeMP4, duration, nil
+	}
+	return MediaTypeM4A, duration, nil
+}
+
+// mp4Duration reads the duration from the body of a mvhd box.
+func mp4Duration(r io.ReaderAt, body int64) (time.Duration, error) {
+	version, err := readAt(r, body, 1)
+	if err != nil {
+		return 0, err
+	}
+	var timescale, duration uint64
+	if version[0] == 1 {
+		b, err := readAt(r, body+4+16, 12)
+		if err != nil {
+			return 0, err
+		}
+		timescale = uint64(binary.BigEndian.Uint32(b))
+		duration = binary.BigEndian.Uint64(b[4:])
+	} else {
+		b, err := readAt(r, body+4+8, 8)
+		if err != nil {
+			return 0, err
+		}
+		timescale = uint64(binary.BigEndian.Uint32(b))
+		duration = uint64(binary.BigEndian.Uint32(b[4:]))
+	}
+	if timescale == 0 {
+		return 0, fmt.Errorf("%w: mvhd timescale is zero", ErrInvalidMedia)
+	}
+	if duration == 1<<64-1 || duration == 1<<32-1 {
+		// All ones marks an unknown duration.
+		return 0, nil
+	}
+	return samplesDuration(int64(duration), int64(timescale)), nil
+}
+
+// mp4IsVideo reports whether the trak box with given body has a video handler.
+func mp4IsVideo(r io.ReaderAt, body, bodyEnd int64) (bool, error) {
+	video := false
+	err := walkMP4(r, body, bodyEnd, func(boxType string, body, bodyEnd int64) error {
+		if boxType != "mdia" {
+			return nil
+		}
+		return walkMP4(r, body, bodyEnd, func(boxType string, body, bodyEnd int64) error {
+			if boxType != "hdlr" {
+				return nil
+			}
+			handler, err := readAt(r, body+8, 4)
+			if err != nil {
+				return err
+			}
+			video = string(handler) == "vide"
+			return errStopWalk
+		})
+	})
+	if err == errStopWalk {
+		err = nil
+	}
+	return video, err
+}
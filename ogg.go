@@ -0,0 +1,82 @@
+package podcasts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// oggPageHeaderSize is the size of an Ogg page header without its segment table.
+	oggPageHeaderSize = 27
+	// oggMaxPageSize is the largest possible Ogg page, header and segment table included.
+	oggMaxPageSize = oggPageHeaderSize + 255 + 255*255
+	// opusSampleRate is the rate of the granule position of Opus streams.
+	opusSampleRate = 48000
+)
+
+// inspectOgg returns the duration of the Ogg Opus or Ogg Vorbis audio of given size.
+func inspectOgg(r io.ReaderAt, size int64) (time.Duration, error) {
+	header, err := readAt(r, 0, oggPageHeaderSize)
+	if err != nil {
+		return 0, err
+	}
+	serial := binary.LittleEndian.Uint32(header[14:18])
+	segments, err := readAt(r, oggPageHeaderSize, int(header[26]))
+	if err != nil {
+		return 0, err
+	}
+	bodySize := 0
+	for _, segment := range segments {
+		bodySize += int(segment)
+	}
+	if bodySize > 19 {
+		bodySize = 19
+	}
+	body, err := readAt(r, int64(oggPageHeaderSize+len(segments)), bodySize)
+	if err != nil {
+		return 0, err
+	}
+
+	var sampleRate, preSkip int64
+	switch {
+	case len(body) >= 12 && string(body[:8]) == "OpusHead":
+		sampleRate = opusSampleRate
+		preSkip = int64(binary.LittleEndian.Uint16(body[10:12]))
+	case len(body) >= 16 && body[0] == 1 && string(body[1:7]) == "vorbis":
+		sampleRate = int64(binary.LittleEndian.Uint32(body[12:16]))
+	default:
+		return 0, ErrUnknownMedia
+	}
+
+	granule, err := lastOggGranule(r, size, serial)
+	if err != nil {
+		return 0, err
+	}
+	return samplesDuration(granule-preSkip, sampleRate), nil
+}
+
+// lastOggGranule returns the granule position of the last page of the stream with given serial.
+func lastOggGranule(r io.ReaderAt, size int64, serial uint32) (int64, error) {
+	tailSize := int64(oggMaxPageSize)
+	if tailSize > size {
+		tailSize = size
+	}
+	tail, err := readAt(r, size-tailSize, int(tailSize))
+	if err != nil {
+		return 0, err
+	}
+	for i := bytes.LastIndex(tail, []byte("OggS")); i >= 0; i = bytes.LastIndex(tail[:i], []byte("OggS")) {
+		if i+oggPageHeaderSize > len(tail) || binary.LittleEndian.Uint32(tail[i+14:]) != serial {
+			continue
+		}
+		granule := binary.LittleEndian.Uint64(tail[i+6:])
+		if granule != 1<<64-1 {
+			// All ones marks a page on which no packet ends.
+			return int64(granule), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: no Ogg page with a granule position found", ErrInvalidMedia)
+}
@@ -3,6 +3,7 @@ package podcasts
 import (
 	"errors"
 	"net/url"
+	"time"
 )
 
 var (
@@ -11,6 +12,18 @@ var (
 
 	// ErrInvalidImage represents a error returned for invalid image.
 	ErrInvalidImage = errors.New("podcasts: invalid image")
+
+	// ErrInvalidShowType represents a error returned for invalid show type.
+	ErrInvalidShowType = errors.New("podcasts: invalid show type")
+
+	// ErrInvalidTTL represents a error returned for invalid ttl.
+	ErrInvalidTTL = errors.New("podcasts: invalid ttl")
+
+	// ErrInvalidSkipHour represents a error returned for invalid hour to skip.
+	ErrInvalidSkipHour = errors.New("podcasts: invalid skip hour")
+
+	// ErrInvalidSkipDay represents a error returned for invalid day to skip.
+	ErrInvalidSkipDay = errors.New("podcasts: invalid skip day")
 )
 
 const (
@@ -26,6 +39,17 @@ func Author(author string) func(f *Feed) error {
 	}
 }
 
+// Type sets itunes:type of given feed.
+func Type(showType ShowType) func(f *Feed) error {
+	return func(f *Feed) error {
+		if showType != Episodic && showType != Serial {
+			return ErrInvalidShowType
+		}
+		f.Channel.Type = showType
+		return nil
+	}
+}
+
 // Block enables itunes:block of given feed.
 func Block(f *Feed) error {
 	f.Channel.Block = ValueYes
@@ -38,6 +62,13 @@ func Explicit(f *Feed) error {
 	return nil
 }
 
+// Clean sets itunes:explicit of given feed to false, for feeds without
+// explicit content, as Apple Podcasts requires the tag either way.
+func Clean(f *Feed) error {
+	f.Channel.Explicit = "false"
+	return nil
+}
+
 // Complete enables itunes:complete of given feed.
 func Complete(f *Feed) error {
 	f.Channel.Complete = ValueYes
@@ -102,3 +133,154 @@ func Image(href string) func(feed *Feed) error {
 		return nil
 	}
 }
+
+// Locked enables podcast:locked of given feed, with the email of the owner
+// allowed to move the feed to another host.
+func Locked(owner string) func(f *Feed) error {
+	return func(f *Feed) error {
+		f.Channel.Locked = &PodcastLocked{
+			Owner: owner,
+			Value: ValueYes,
+		}
+		return nil
+	}
+}
+
+// Funding adds a podcast:funding link of given feed.
+func Funding(fundingURL, text string) func(f *Feed) error {
+	return func(f *Feed) error {
+		u, err := url.Parse(fundingURL)
+		if err != nil {
+			return err
+		}
+		if !u.IsAbs() {
+			return ErrInvalidURL
+		}
+		f.Channel.Funding = append(f.Channel.Funding, &PodcastFunding{
+			URL:   fundingURL,
+			Value: text,
+		})
+		return nil
+	}
+}
+
+// SelfLink sets the atom:link to the feed itself, as recommended by RSS validators.
+func SelfLink(selfURL string) func(f *Feed) error {
+	return func(f *Feed) error {
+		u, err := url.Parse(selfURL)
+		if err != nil {
+			return err
+		}
+		if !u.IsAbs() {
+			return ErrInvalidURL
+		}
+		links := make([]*AtomLink, 0, len(f.Channel.AtomLinks)+1)
+		for _, link := range f.Channel.AtomLinks {
+			if link == nil || link.Rel != "self" {
+				links = append(links, link)
+			}
+		}
+		f.Channel.AtomLinks = append(links, &AtomLink{
+			Href: selfURL,
+			Rel:  "self",
+			Type: "application/rss+xml",
+		})
+		return nil
+	}
+}
+
+// TTL sets ttl of given feed, the time aggregators may cache the feed for.
+// It is rounded down to whole minutes.
+func TTL(ttl time.Duration) func(f *Feed) error {
+	return func(f *Feed) error {
+		if ttl < time.Minute {
+			return ErrInvalidTTL
+		}
+		f.Channel.TTL = int(ttl / time.Minute)
+		return nil
+	}
+}
+
+// Generator sets generator of given feed.
+func Generator(generator string) func(f *Feed) error {
+	return func(f *Feed) error {
+		f.Channel.Generator = generator
+		return nil
+	}
+}
+
+// ManagingEditor sets managingEditor of given feed, e.g. "editor@example.com (Editor Name)".
+func ManagingEditor(editor string) func(f *Feed) error {
+	return func(f *Feed) error {
+		f.Channel.ManagingEditor = editor
+		return nil
+	}
+}
+
+// WebMaster sets webMaster of given feed, e.g. "webmaster@example.com (Webmaster Name)".
+func WebMaster(webMaster string) func(f *Feed) error {
+	return func(f *Feed) error {
+		f.Channel.WebMaster = webMaster
+		return nil
+	}
+}
+
+// Docs sets docs of given feed.
+func Docs(docsURL string) func(f *Feed) error {
+	return func(f *Feed) error {
+		u, err := url.Parse(docsURL)
+		if err != nil {
+			return err
+		}
+		if !u.IsAbs() {
+			return ErrInvalidURL
+		}
+		f.Channel.Docs = docsURL
+		return nil
+	}
+}
+
+// Published sets pubDate of given feed.
+func Published(t time.Time) func(f *Feed) error {
+	return func(f *Feed) error {
+		f.Channel.PubDate = NewPubDate(t)
+		return nil
+	}
+}
+
+// LastBuildDate sets lastBuildDate of given feed. When not set,
+// Podcast.Feed uses the pubDate of the newest item.
+func LastBuildDate(t time.Time) func(f *Feed) error {
+	return func(f *Feed) error {
+		f.Channel.LastBuildDate = NewPubDate(t)
+		return nil
+	}
+}
+
+// SkipHours sets skipHours of given feed, hours are from 0 to 23 in GMT.
+func SkipHours(hours ...int) func(f *Feed) error {
+	return func(f *Feed) error {
+		for _, hour := range hours {
+			if hour < 0 || hour > 23 {
+				return ErrInvalidSkipHour
+			}
+		}
+		f.Channel.SkipHours = &SkipHourList{Hours: hours}
+		return nil
+	}
+}
+
+// SkipDays sets skipDays of given feed.
+func SkipDays(days ...time.Weekday) func(f *Feed) error {
+	return func(f *Feed) error {
+		names := make([]string, 0, len(days))
+		for _, day := range days {
+			if day < time.Sunday || day > time.Saturday {
+				return ErrInvalidSkipDay
+			}
+			names = append(names, day.String())
+		}
+		f.Channel.SkipDays = &SkipDayList{Days: names}
+		return nil
+	}
+}
@@ -3,6 +3,7 @@ package podcasts
 import (
 	"errors"
 	"testing"
+	"time"
 )
 
 const (
@@ -47,6 +48,21 @@ func TestExplicit(t *testing.T) {
 	}
 }
 
+func TestClean(t *testing.T) {
+	feed := &Feed{
+		Channel: &Channel{},
+	}
+	if err := Clean(feed); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if feed.Channel.Explicit != "false" {
+		t.Errorf("expected %v got %v", "false", feed.Channel.Explicit)
+	}
+	if problems := feed.Validate(); hasProblem(problems, "Channel.Explicit", SeverityError) {
+		t.Errorf("unexpected explicit problem in %v", problems)
+	}
+}
+
 func TestComplete(t *testing.T) {
 	feed := &Feed{
 		Channel: &Channel{},
@@ -207,3 +223,187 @@ func TestSetOptionsWithMultipleOptions(t *testing.T) {
 		t.Errorf("expected subtitle to be set")
 	}
 }
+
+func TestLocked(t *testing.T) {
+	feed := &Feed{
+		Channel: &Channel{},
+	}
+	owner := "owner@example.com"
+	if err := Locked(owner)(feed); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if owner != feed.Channel.Locked.Owner {
+		t.Errorf("expected %v got %v", owner, feed.Channel.Locked.Owner)
+	}
+	if ValueYes != feed.Channel.Locked.Value {
+		t.Errorf("expected %v got %v", ValueYes, feed.Channel.Locked.Value)
+	}
+}
+
+func TestFunding(t *testing.T) {
+	feed := &Feed{
+		Channel: &Channel{},
+	}
+	if err := Funding("https://example.com/donate", "Support the show")(feed); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := Funding("https://example.com/members", "Become a member")(feed); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if len(feed.Channel.Funding) != 2 {
+		t.Fatalf("expected 2 funding links got %d", len(feed.Channel.Funding))
+	}
+	if feed.Channel.Funding[1].URL != "https://example.com/members" {
+		t.Errorf("expected %v got %v", "https://example.com/members", feed.Channel.Funding[1].URL)
+	}
+}
+
+func TestFundingInvalid(t *testing.T) {
+	feed := &Feed{
+		Channel: &Channel{},
+	}
+	if err := Funding("/donate", "Support the show")(feed); !errors.Is(err, ErrInvalidURL) {
+		t.Errorf("expected ErrInvalidURL, got %v", err)
+	}
+	if err := Funding("http://example.com/donate\x00", "Support the show")(feed); err == nil {
+		t.Error("expected error for funding URL with null characters")
+	}
+}
+
+func TestType(t *testing.T) {
+	feed := &Feed{
+		Channel: &Channel{},
+	}
+	if err := Type(Serial)(feed); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if Serial != feed.Channel.Type {
+		t.Errorf("expected %v got %v", Serial, feed.Channel.Type)
+	}
+}
+
+func TestTypeInvalid(t *testing.T) {
+	feed := &Feed{
+		Channel: &Channel{},
+	}
+	if err := Type("daily")(feed); !errors.Is(err, ErrInvalidShowType) {
+		t.Errorf("expected ErrInvalidShowType, got %v", err)
+	}
+}
+
+func TestSelfLink(t *testing.T) {
+	feed := &Feed{
+		Channel: &Channel{
+			AtomLinks: []*AtomLink{
+				{Href: "http://example.com/old", Rel: "self"},
+				{Href: "http://example.com/hub", Rel: "hub"},
+			},
+		},
+	}
+	href := "http://example.com/feed.xml"
+	if err := SelfLink(href)(feed); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if len(feed.Channel.AtomLinks) != 2 {
+		t.Fatalf("expected self link to be replaced, got %d links", len(feed.Channel.AtomLinks))
+	}
+	link := feed.Channel.AtomLinks[1]
+	if link.Href != href || link.Rel != "self" || link.Type != "application/rss+xml" {
+		t.Errorf("unexpected self link %+v", link)
+	}
+	if err := SelfLink("/feed.xml")(feed); !errors.Is(err, ErrInvalidURL) {
+		t.Errorf("expected ErrInvalidURL, got %v", err)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	feed := &Feed{
+		Channel: &Channel{},
+	}
+	if err := TTL(time.Hour + time.Second*30)(feed); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if feed.Channel.TTL != 60 {
+		t.Errorf("expected %v got %v", 60, feed.Channel.TTL)
+	}
+	if err := TTL(time.Second * 30)(feed); !errors.Is(err, ErrInvalidTTL) {
+		t.Errorf("expected ErrInvalidTTL, got %v", err)
+	}
+}
+
+func TestChannelTextOptions(t *testing.T) {
+	feed := &Feed{
+		Channel: &Channel{},
+	}
+	err := feed.SetOptions(
+		Generator("podcasts"),
+		ManagingEditor("editor@example.com (Editor)"),
+		WebMaster("webmaster@example.com (Webmaster)"),
+		Docs("https://www.rssboard.org/rss-specification"),
+	)
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if feed.Channel.Generator != "podcasts" {
+		t.Errorf("expected %v got %v", "podcasts", feed.Channel.Generator)
+	}
+	if feed.Channel.ManagingEditor != "editor@example.com (Editor)" {
+		t.Errorf("expected %v got %v", "editor@example.com (Editor)", feed.Channel.ManagingEditor)
+	}
+	if feed.Channel.WebMaster != "webmaster@example.com (Webmaster)" {
+		t.Errorf("expected %v got %v", "webmaster@example.com (Webmaster)", feed.Channel.WebMaster)
+	}
+	if feed.Channel.Docs != "https://www.rssboard.org/rss-specification" {
+		t.Errorf("expected %v got %v", "https://www.rssboard.org/rss-specification", feed.Channel.Docs)
+	}
+	if err := Docs("rss-specification")(feed); !errors.Is(err, ErrInvalidURL) {
+		t.Errorf("expected ErrInvalidURL, got %v", err)
+	}
+}
+
+func TestChannelDateOptions(t *testing.T) {
+	feed := &Feed{
+		Channel: &Channel{},
+	}
+	published := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	built := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	if err := feed.SetOptions(Published(published), LastBuildDate(built)); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if !feed.Channel.PubDate.Equal(published) {
+		t.Errorf("expected %v got %v", published, feed.Channel.PubDate)
+	}
+	if !feed.Channel.LastBuildDate.Equal(built) {
+		t.Errorf("expected %v got %v", built, feed.Channel.LastBuildDate)
+	}
+}
+
+func TestSkipHours(t *testing.T) {
+	feed := &Feed{
+		Channel: &Channel{},
+	}
+	if err := SkipHours(0, 1, 23)(feed); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if len(feed.Channel.SkipHours.Hours) != 3 {
+		t.Errorf("expected 3 hours got %v", feed.Channel.SkipHours.Hours)
+	}
+	if err := SkipHours(24)(feed); !errors.Is(err, ErrInvalidSkipHour) {
+		t.Errorf("expected ErrInvalidSkipHour, got %v", err)
+	}
+}
+
+func TestSkipDays(t *testing.T) {
+	feed := &Feed{
+		Channel: &Channel{},
+	}
+	if err := SkipDays(time.Saturday, time.Sunday)(feed); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if got := feed.Channel.SkipDays.Days; len(got) != 2 || got[0] != "Saturday" || got[1] != "Sunday" {
+		t.Errorf("unexpected days %v", got)
+	}
+	if err := SkipDays(time.Weekday(7))(feed); !errors.Is(err, ErrInvalidSkipDay) {
+		t.Errorf("expected ErrInvalidSkipDay, got %v", err)
+	}
+}
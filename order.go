@@ -0,0 +1,117 @@
+package podcasts
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrDuplicateGUID represents a error returned when adding an item whose guid is already used.
+var ErrDuplicateGUID = errors.New("podcasts: duplicate guid")
+
+// ItemOrder represents the order of the items of given podcast feed.
+type ItemOrder int
+
+const (
+	// InsertionOrder keeps items in the order they were added.
+	InsertionOrder ItemOrder = iota
+	// NewestFirst sorts items by pubDate, newest first. Items without pubDate come last.
+	NewestFirst
+	// ItunesOrder sorts items by itunes:order, then newest first. Items without itunes:order come last.
+	ItunesOrder
+	// EpisodeOrder sorts items by itunes:season and itunes:episode, as
+	// expected of serial shows, then oldest first. Items without season or
+	// episode numbers come last.
+	EpisodeOrder
+)
+
+// DuplicatePolicy represents what AddItem does with an item whose guid is
+// already used by an item of given podcast. Items without guid are never duplicates.
+type DuplicatePolicy int
+
+const (
+	// DuplicateAllow adds the item anyway, leaving both in the feed.
+	DuplicateAllow DuplicatePolicy = iota
+	// DuplicateError rejects the item: TryAddItem returns ErrDuplicateGUID
+	// and AddItem drops it.
+	DuplicateError
+	// DuplicateKeepFirst ignores the item, keeping the one added first.
+	DuplicateKeepFirst
+	// DuplicateKeepLatest replaces the item added first with the new one, in its place.
+	DuplicateKeepLatest
+)
+
+// sortItems returns a copy of given items sorted in given order. Items that
+// compare equal are sorted by guid then title, so that the order does not
+// depend on the order in which they were added.
+func sortItems(items []*Item, order ItemOrder) []*Item {
+	if order == InsertionOrder {
+		return items
+	}
+	sorted := make([]*Item, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a == nil || b == nil {
+			return b == nil && a != nil
+		}
+		if c := compareItems(a, b, order); c != 0 {
+			return c < 0
+		}
+		if a.GUID != b.GUID {
+			return a.GUID < b.GUID
+		}
+		return a.Title < b.Title
+	})
+	return sorted
+}
+
+// compareItems returns a negative number when a comes before b in given order,
+// a positive number when it comes after b, and 0 when they are equal.
+func compareItems(a, b *Item, order ItemOrder) int {
+	switch order {
+	case ItunesOrder:
+		if c := compareNumbers(a.Order, b.Order); c != 0 {
+			return c
+		}
+		return -comparePubDates(a, b)
+	case EpisodeOrder:
+		if c := compareNumbers(a.Season, b.Season); c != 0 {
+			return c
+		}
+		if c := compareNumbers(a.Episode, b.Episode); c != 0 {
+			return c
+		}
+		return comparePubDates(a, b)
+	default:
+		return -comparePubDates(a, b)
+	}
+}
+
+// compareNumbers compares itunes numbers ascending, 0 meaning unset and coming last.
+func compareNumbers(a, b int) int {
+	switch {
+	case a == b:
+		return 0
+	case a == 0:
+		return 1
+	case b == 0:
+		return -1
+	case a < b:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// comparePubDates compares the pubDates of given items ascending. Items
+// without pubDate compare as the oldest.
+func comparePubDates(a, b *Item) int {
+	switch {
+	case pubDateBefore(a, b):
+		return -1
+	case pubDateBefore(b, a):
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,115 @@
+package podcasts
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func day(d int) *PubDate {
+	return NewPubDate(time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC))
+}
+
+func setupOrderedPodcast(order ItemOrder, items ...*Item) *Podcast {
+	p := &Podcast{Title: "Ordered", Order: order}
+	for _, item := range items {
+		p.AddItem(item)
+	}
+	return p
+}
+
+func TestItemOrder(t *testing.T) {
+	items := []*Item{
+		{Title: "S1E2", GUID: "c", PubDate: day(2), Season: 1, Episode: 2, Order: 2},
+		{Title: "Bonus", GUID: "z", PubDate: day(4)},
+		{Title: "S2E1", GUID: "d", PubDate: day(3), Season: 2, Episode: 1, Order: 1},
+		{Title: "S1E1", GUID: "b", PubDate: day(1), Season: 1, Episode: 1, Order: 3},
+		{Title: "Undated", GUID: "a"},
+		{Title: "S1E1 repeat", GUID: "e", PubDate: day(2), Season: 1, Episode: 1},
+	}
+	cases := map[ItemOrder]string{
+		InsertionOrder: "S1E2, Bonus, S2E1, S1E1, Undated, S1E1 repeat",
+		NewestFirst:    "Bonus, S2E1, S1E2, S1E1 repeat, S1E1, Undated",
+		ItunesOrder:    "S2E1, S1E2, S1E1, Bonus, S1E1 repeat, Undated",
+		EpisodeOrder:   "S1E1, S1E1 repeat, S1E2, S2E1, Undated, Bonus",
+	}
+	for order, want := range cases {
+		feed, err := setupOrderedPodcast(order, items...).Feed()
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if got := titles(feed.Channel.Items); got != want {
+			t.Errorf("%d: expected %v got %v", order, want, got)
+		}
+	}
+}
+
+func TestItemOrderDeterministic(t *testing.T) {
+	// Items published at the same time are sorted by guid, whatever the order they were added in.
+	a := &Item{Title: "A", GUID: "https://example.com/a", PubDate: day(1)}
+	b := &Item{Title: "B", GUID: "https://example.com/b", PubDate: day(1)}
+	first, _ := setupOrderedPodcast(NewestFirst, a, b).Feed(LastBuildDate(day(1).Time))
+	second, _ := setupOrderedPodcast(NewestFirst, b, a).Feed(LastBuildDate(day(1).Time))
+	var want, got bytes.Buffer
+	if err := first.Write(&want); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := second.Write(&got); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("expected %v got %v", want.String(), got.String())
+	}
+}
+
+func TestDuplicatePolicy(t *testing.T) {
+	first := &Item{Title: "First", GUID: "https://example.com/1"}
+	latest := &Item{Title: "Latest", GUID: "https://example.com/1"}
+	other := &Item{Title: "Other", GUID: "https://example.com/2"}
+	cases := map[DuplicatePolicy]string{
+		DuplicateAllow:      "First, Other, Latest",
+		DuplicateKeepFirst:  "First, Other",
+		DuplicateKeepLatest: "Latest, Other",
+	}
+	for policy, want := range cases {
+		p := &Podcast{Duplicates: policy}
+		for _, item := range []*Item{first, other, latest} {
+			p.AddItem(item)
+		}
+		if got := titles(p.Items()); got != want {
+			t.Errorf("%d: expected %v got %v", policy, want, got)
+		}
+	}
+
+	p := &Podcast{Duplicates: DuplicateError}
+	if err := p.TryAddItem(first); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := p.TryAddItem(latest); err != ErrDuplicateGUID {
+		t.Errorf("expected %v got %v", ErrDuplicateGUID, err)
+	}
+	if err := p.TryAddItem(&Item{Title: "No guid"}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := p.TryAddItem(&Item{Title: "No guid either"}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := p.TryAddItem(other); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	// AddItem drops duplicates it cannot report.
+	p.AddItem(latest)
+	if got := titles(p.Items()); got != "First, No guid, No guid either, Other" {
+		t.Errorf("expected %v got %v", "First, No guid, No guid either, Other", got)
+	}
+	err := p.UpdateItem(other.GUID, func(item *Item) error {
+		item.GUID = first.GUID
+		return nil
+	})
+	if err != ErrDuplicateGUID {
+		t.Errorf("expected %v got %v", ErrDuplicateGUID, err)
+	}
+	if got := titles(p.Items()); got != "First, No guid, No guid either, Other" {
+		t.Errorf("expected %v got %v", "First, No guid, No guid either, Other", got)
+	}
+}
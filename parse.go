@@ -0,0 +1,157 @@
+package podcasts
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// xmlURI is the namespace of the xml prefix, which is never declared.
+const xmlURI = "http://www.w3.org/XML/1998/namespace"
+
+// prefixes maps the namespace URIs understood by the parser to the
+// prefixes used in the struct tags of the feed types.
+var prefixes = map[string]string{
+	itunesXMLNS:  "itunes",
+	contentXMLNS: "content",
+	atomXMLNS:    "atom",
+	podcastXMLNS: "podcast",
+	historyXMLNS: "fh",
+	pscXMLNS:     "psc",
+}
+
+// Parse reads an RSS podcast feed from r. Elements and attributes of other
+// namespaces are read into the Extensions and ExtensionAttrs of the channel
+// and items, with the prefixes declared by the feed, and their namespaces
+// into Namespaces, so that they are written back. Dates and durations that
+// cannot be read are left out.
+func Parse(r io.Reader) (*Feed, error) {
+	feed := &Feed{}
+	pr := &prefixReader{dec: xml.NewDecoder(r), extensions: make(map[string]string)}
+	dec := xml.NewTokenDecoder(pr)
+	if err := dec.Decode(feed); err != nil {
+		return nil, err
+	}
+	// Namespaces declared below the rss element are declared on it instead.
+	for _, ns := range pr.declared {
+		if !feed.declares(ns.Prefix) {
+			feed.Namespaces = append(feed.Namespaces, ns)
+		}
+	}
+	return feed, nil
+}
+
+// declares reports whether prefix is in the namespaces of the feed.
+func (f *Feed) declares(prefix string) bool {
+	for _, ns := range f.Namespaces {
+		if ns != nil && ns.Prefix == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// prefixReader is a xml.TokenReader which rewrites namespaced names back
+// into the literal "prefix:local" form used by the struct tags, so that
+// the feed types can be decoded regardless of the prefixes a feed declares.
+// Names in other namespaces use the prefix declared by the feed, or a new
+// one if it is already used, so that they never match the unprefixed RSS
+// fields: googleplay:description is not read as description.
+type prefixReader struct {
+	dec *xml.Decoder
+	// extensions maps the namespace URIs not understood by the parser to their prefixes.
+	extensions map[string]string
+	// declared lists the namespaces of extensions in the order they were found.
+	declared []*Namespace
+	depth    int
+}
+
+// Token returns the next token with known namespaces rewritten.
+func (r *prefixReader) Token() (xml.Token, error) {
+	tok, err := r.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case xml.StartElement:
+		// Namespaces are registered first, as the element may use the ones it declares.
+		for _, attr := range t.Attr {
+			if attr.Name.Space == "xmlns" {
+				r.prefix(attr.Value, attr.Name.Local)
+			}
+		}
+		t.Name = r.prefixName(t.Name)
+		attrs := make([]xml.Attr, 0, len(t.Attr))
+		for _, attr := range t.Attr {
+			switch {
+			case attr.Name.Space == "xmlns" && r.depth > 0:
+				// Namespaces of other elements are declared on the rss element by Parse.
+				continue
+			case attr.Name.Space == "xmlns":
+				attr.Name = xml.Name{Local: "xmlns:" + r.prefix(attr.Value, attr.Name.Local)}
+			case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+				// Default namespaces are not needed once every name is prefixed.
+				continue
+			default:
+				attr.Name = r.prefixName(attr.Name)
+			}
+			attrs = append(attrs, attr)
+		}
+		t.Attr = attrs
+		r.depth++
+		return t, nil
+	case xml.EndElement:
+		t.Name = r.prefixName(t.Name)
+		r.depth--
+		return t, nil
+	}
+	return tok, nil
+}
+
+// prefixName rewrites a namespaced name to its prefixed form.
+func (r *prefixReader) prefixName(name xml.Name) xml.Name {
+	switch {
+	case name.Space == "":
+		return name
+	case name.Space == xmlURI:
+		return xml.Name{Local: "xml:" + name.Local}
+	case !strings.ContainsAny(name.Space, ":/"):
+		// The decoder leaves undeclared prefixes as they are.
+		return xml.Name{Local: name.Space + ":" + name.Local}
+	}
+	return xml.Name{Local: r.prefix(name.Space, "") + ":" + name.Local}
+}
+
+// prefix returns the prefix of given namespace URI, registering it with
+// preferred, or a new prefix if preferred is empty or already used.
+func (r *prefixReader) prefix(uri, preferred string) string {
+	if prefix, ok := prefixes[uri]; ok {
+		return prefix
+	}
+	if prefix, ok := r.extensions[uri]; ok {
+		return prefix
+	}
+	prefix := preferred
+	for n := 1; prefix == "" || r.used(prefix); n++ {
+		prefix = "ns" + strconv.Itoa(n)
+	}
+	r.extensions[uri] = prefix
+	r.declared = append(r.declared, &Namespace{Prefix: prefix, URI: uri})
+	return prefix
+}
+
+// used reports whether prefix is used by a namespace understood by the parser or by an extension.
+func (r *prefixReader) used(prefix string) bool {
+	for _, p := range prefixes {
+		if p == prefix {
+			return true
+		}
+	}
+	for _, ns := range r.declared {
+		if ns.Prefix == prefix {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,246 @@
+package podcasts
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRoundTrip(t *testing.T) {
+	podcast := setupPodcast()
+	podcast.Title = "Round Trip"
+	podcast.Description = "A podcast that survives parsing"
+	podcast.Language = "en"
+	podcast.Link = "https://example.com"
+	podcast.Copyright = "2024"
+	podcast.AddItem(&Item{
+		Title:           "Item 4",
+		GUID:            "http://www.example-podcast.com/my-podcast/4/episode",
+		PubDate:         NewPubDate(time.Date(2015, time.January, 4, 10, 30, 0, 0, time.FixedZone("", -5*60*60))),
+		Duration:        NewDuration(time.Hour + time.Second*5),
+		Author:          testAuthor,
+		Block:           ValueYes,
+		Explicit:        ValueYes,
+		ClosedCaptioned: ValueYes,
+		Order:           4,
+		Subtitle:        testSubtitle,
+		Summary:         &CDATAText{Value: "<p>Summary with <b>markup</b></p>"},
+		Enclosure: &Enclosure{
+			URL:    "http://www.example-podcast.com/my-podcast/4/episode.mp3",
+			Length: "4321",
+			Type:   "audio/mpeg",
+		},
+		Image: &ItunesImage{Href: "http://www.example-podcast.com/my-podcast/4/image.jpg"},
+	})
+
+	feed, err := podcast.Feed(
+		Author(testAuthor),
+		Block,
+		Explicit,
+		Complete,
+		NewFeedURL("http://www.example-podcast.com/new-feed-url"),
+		Subtitle(testSubtitle),
+		Summary("Summary with <a href=\"http://example.com\">link</a>"),
+		Owner("Podcast Owner", "owner@example-podcast.com"),
+		Image("http://www.example-podcast.com/my-podcast.jpg"),
+		SelfLink("http://www.example-podcast.com/feed.xml"),
+		TTL(time.Hour),
+		SkipHours(0, 1),
+		SkipDays(time.Sunday),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	feed.Channel.Items[0].GUIDIsPermaLink = "false"
+	feed.Channel.Categories = []*ItunesCategory{
+		{Text: "Technology"},
+		{Text: "Society & Culture", Categories: []*ItunesCategory{{Text: "Documentary"}}},
+	}
+
+	want, err := feed.XML()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	parsed, err := Parse(strings.NewReader(want))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	got, err := parsed.XML()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if want != got {
+		t.Errorf("expected %v got %v", want, got)
+	}
+
+	if len(parsed.Channel.Items) != 4 {
+		t.Fatalf("expected 4 items got %d", len(parsed.Channel.Items))
+	}
+	item := parsed.Channel.Items[3]
+	if item.Duration.Duration != time.Hour+time.Second*5 {
+		t.Errorf("expected %v got %v", time.Hour+time.Second*5, item.Duration.Duration)
+	}
+	if !item.PubDate.Equal(time.Date(2015, time.January, 4, 15, 30, 0, 0, time.UTC)) {
+		t.Errorf("unexpected pubDate %v", item.PubDate.Time)
+	}
+	if item.Summary.Value != "<p>Summary with <b>markup</b></p>" {
+		t.Errorf("unexpected summary %v", item.Summary.Value)
+	}
+	if parsed.Channel.Items[0].GUIDIsPermaLink != "false" {
+		t.Errorf("expected %v got %v", "false", parsed.Channel.Items[0].GUIDIsPermaLink)
+	}
+	category := parsed.Channel.Categories[1]
+	if category.Text != "Society & Culture" || category.Categories[0].Text != "Documentary" {
+		t.Errorf("unexpected category %+v", category)
+	}
+}
+
+func TestParseNamespacePrefixes(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<rss xmlns:itms="http://www.itunes.com/dtds/podcast-1.0.dtd" xmlns:c="http://purl.org/rss/1.0/modules/content/" version="2.0">
+  <channel>
+    <title>Prefixes</title>
+    <itms:author>Someone</itms:author>
+    <itms:image href="https://example.com/image.jpg"/>
+    <item>
+      <title>Episode</title>
+      <guid>1</guid>
+      <c:encoded>Plain content</c:encoded>
+      <itms:duration>125</itms:duration>
+    </item>
+  </channel>
+</rss>`
+	feed, err := Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	wantNamespaces := []*Namespace{{Prefix: "itunes", URI: itunesXMLNS}, {Prefix: "content", URI: contentXMLNS}}
+	if !reflect.DeepEqual(feed.Namespaces, wantNamespaces) {
+		t.Errorf("expected %+v got %+v", wantNamespaces, feed.Namespaces)
+	}
+	if feed.Channel.Author != "Someone" {
+		t.Errorf("expected %v got %v", "Someone", feed.Channel.Author)
+	}
+	if feed.Channel.Image == nil || feed.Channel.Image.Href != "https://example.com/image.jpg" {
+		t.Errorf("unexpected image %+v", feed.Channel.Image)
+	}
+	item := feed.Channel.Items[0]
+	if item.ContentEncoded == nil || item.ContentEncoded.Value != "Plain content" {
+		t.Errorf("unexpected content %+v", item.ContentEncoded)
+	}
+	if item.Duration == nil || item.Duration.Duration != time.Second*125 {
+		t.Errorf("unexpected duration %+v", item.Duration)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]string{
+		"Malformed": `<rss><channel>`,
+		"WrongRoot": `<feed xmlns="http://www.w3.org/2005/Atom"></feed>`,
+	}
+	for name, data := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(data)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestParseInvalidDates(t *testing.T) {
+	data := `<rss xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd"><channel>
+  <pubDate>yesterday</pubDate>
+  <lastBuildDate>Thu, 01 Jan 2015 00:00:00 +0000</lastBuildDate>
+  <item><title>Episode 1</title><pubDate>yesterday</pubDate><itunes:duration>1:x</itunes:duration></item>
+  <item><title>Episode 2</title><pubDate>Thu, 01 Jan 2015 00:00:00 +0000</pubDate><itunes:duration>1:04</itunes:duration></item>
+</channel></rss>`
+	feed, err := Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	if feed.Channel.PubDate != nil || feed.Channel.LastBuildDate == nil || !feed.Channel.LastBuildDate.Equal(want) {
+		t.Errorf("unexpected channel dates %v %v", feed.Channel.PubDate, feed.Channel.LastBuildDate)
+	}
+	if len(feed.Channel.Items) != 2 {
+		t.Fatalf("expected 2 items got %d", len(feed.Channel.Items))
+	}
+	if first := feed.Channel.Items[0]; first.PubDate != nil || first.Duration != nil {
+		t.Errorf("expected invalid values to be left out got %v %v", first.PubDate, first.Duration)
+	}
+	second := feed.Channel.Items[1]
+	if second.PubDate == nil || !second.PubDate.Equal(want) || second.Duration == nil || second.Duration.Duration != 64*time.Second {
+		t.Errorf("unexpected values %v %v", second.PubDate, second.Duration)
+	}
+}
+
+func TestDurationUnmarshalling(t *testing.T) {
+	cases := map[string]time.Duration{
+		"<Duration>0:00</Duration>":     0,
+		"<Duration>1:04</Duration>":     time.Second * 64,
+		"<Duration>1:00:00</Duration>":  time.Hour,
+		"<Duration>10:16:40</Duration>": time.Second * 37000,
+		"<Duration> 94 </Duration>":     time.Second * 94,
+	}
+	for data, want := range cases {
+		t.Run(data, func(t *testing.T) {
+			var dur Duration
+			if err := xml.Unmarshal([]byte(data), &dur); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if dur.Duration != want {
+				t.Errorf("expected %v got %v", want, dur.Duration)
+			}
+		})
+	}
+}
+
+func TestPubDateUnmarshalling(t *testing.T) {
+	var pubDate PubDate
+	if err := xml.Unmarshal([]byte("<PubDate>Thu, 01 Jan 2015 00:00:00 +0000</PubDate>"), &pubDate); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := time.Date(2015, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if !pubDate.Equal(want) {
+		t.Errorf("expected %v got %v", want, pubDate.Time)
+	}
+}
+
+func TestParseForeignElements(t *testing.T) {
+	data := `<rss xmlns:atom="http://www.w3.org/2005/Atom" xmlns:googleplay="http://www.google.com/schemas/play-podcasts/1.0" version="2.0">
+<channel>
+<title>Title</title>
+<link>https://example.com</link>
+<atom:link href="https://example.com/feed.xml" rel="self" type="application/rss+xml"/>
+<description>Description</description>
+<googleplay:description>Google Play description</googleplay:description>
+<googleplay:image href="https://example.com/google.jpg"/>
+<item>
+<title>Episode 1</title>
+<googleplay:title>Google Play title</googleplay:title>
+<description>Episode description</description>
+<googleplay:description>Google Play episode description</googleplay:description>
+</item>
+</channel>
+</rss>`
+	feed, err := Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	c := feed.Channel
+	if c.Link != "https://example.com" || c.Description != "Description" {
+		t.Errorf("unexpected channel link %v and description %v", c.Link, c.Description)
+	}
+	if len(c.AtomLinks) != 1 || c.AtomLinks[0].Href != "https://example.com/feed.xml" || c.AtomLinks[0].Rel != "self" {
+		t.Errorf("unexpected atom links %+v", c.AtomLinks)
+	}
+	if c.Image != nil {
+		t.Errorf("unexpected image %+v", c.Image)
+	}
+	item := c.Items[0]
+	if item.Title != "Episode 1" || item.Description == nil || item.Description.Value != "Episode description" {
+		t.Errorf("unexpected item %+v", item)
+	}
+}
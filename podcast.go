@@ -1,35 +1,268 @@
 package podcasts
 
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrItemNotFound represents a error returned when no item has the given guid.
+var ErrItemNotFound = errors.New("podcasts: item not found")
+
 // Podcast represents a web podcast.
+//
+// Its methods are safe for concurrent use, so that feeds can be served while
+// items are added, updated or removed. Items must not be modified once added,
+// except through UpdateItem.
 type Podcast struct {
 	Title       string
 	Description string
 	Link        string
 	Language    string
 	Copyright   string
-	items       []*Item
+
+	// PageSize is the number of items on each page of a paged or archived feed.
+	PageSize int
+	// PageURL returns the absolute url of the page with given number, starting
+	// at 1, of a paged or archived feed.
+	PageURL func(page int) string
+
+	// Order is the order of the items of the feed, InsertionOrder by default.
+	Order ItemOrder
+	// Duplicates is what AddItem does with items whose guid is already used,
+	// DuplicateAllow by default.
+	Duplicates DuplicatePolicy
+
+	mu    sync.RWMutex
+	items []*Item
 }
 
-// AddItem adds an item to the podcast.
+// AddItem adds an item to the podcast. An item whose guid is already used is
+// handled according to the Duplicates policy of the podcast, and dropped under
+// DuplicateError; use TryAddItem to be told about it.
 func (p *Podcast) AddItem(item *Item) {
+	_ = p.TryAddItem(item)
+}
+
+// TryAddItem adds an item to the podcast like AddItem, but returns
+// ErrDuplicateGUID when the Duplicates policy of the podcast is DuplicateError
+// and the guid of the item is already used.
+func (p *Podcast) TryAddItem(item *Item) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if item != nil && item.GUID != "" && p.Duplicates != DuplicateAllow {
+		if i := p.indexOf(item.GUID); i >= 0 {
+			switch p.Duplicates {
+			case DuplicateKeepFirst:
+				return nil
+			case DuplicateKeepLatest:
+				items := make([]*Item, len(p.items))
+				copy(items, p.items)
+				items[i] = item
+				p.items = items
+				return nil
+			default:
+				return ErrDuplicateGUID
+			}
+		}
+	}
 	p.items = append(p.items, item)
+	return nil
+}
+
+// RemoveItem removes the item with given guid, and reports whether it was found.
+func (p *Podcast) RemoveItem(guid string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	i := p.indexOf(guid)
+	if i < 0 {
+		return false
+	}
+	items := make([]*Item, 0, len(p.items)-1)
+	items = append(items, p.items[:i]...)
+	p.items = append(items, p.items[i+1:]...)
+	return true
+}
+
+// UpdateItem updates the item with given guid with fn. The item is changed
+// on a copy, so that feeds being written meanwhile keep the previous version,
+// and left unchanged if fn returns an error.
+func (p *Podcast) UpdateItem(guid string, fn func(item *Item) error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	i := p.indexOf(guid)
+	if i < 0 {
+		return ErrItemNotFound
+	}
+	item := cloneItem(p.items[i])
+	if err := fn(item); err != nil {
+		return err
+	}
+	if p.Duplicates != DuplicateAllow && item != nil && item.GUID != guid && item.GUID != "" && p.indexOf(item.GUID) >= 0 {
+		return ErrDuplicateGUID
+	}
+	// Snapshots taken by Feed share the items slice, so it is replaced rather than modified.
+	items := make([]*Item, len(p.items))
+	copy(items, p.items)
+	items[i] = item
+	p.items = items
+	return nil
 }
 
-// Feed creates a new feed for current podcast.
+// Items returns a copy of the items of the podcast, in the order they were added.
+func (p *Podcast) Items() []*Item {
+	snapshot := p.snapshot()
+	items := make([]*Item, len(snapshot))
+	for i, item := range snapshot {
+		items[i] = cloneItem(item)
+	}
+	return items
+}
+
+// Feed creates a new feed for current podcast, holding the items published
+// so far. Items with a pubDate in the future are left out until then.
 func (p *Podcast) Feed(options ...func(f *Feed) error) (*Feed, error) {
+	return p.FeedAt(time.Now(), options...)
+}
+
+// FeedAt creates a new feed for current podcast as it is at given time,
+// leaving out items with a pubDate after now. Items are sorted in the Order
+// of the podcast.
+func (p *Podcast) FeedAt(now time.Time, options ...func(f *Feed) error) (*Feed, error) {
+	return p.feed(sortItems(published(p.snapshot(), now), p.Order), options...)
+}
+
+// NextPublication returns the pubDate of the next item to be published after
+// now, so that caches of the feed can expire then. It returns false if no
+// item is scheduled.
+func (p *Podcast) NextPublication(now time.Time) (time.Time, bool) {
+	var next time.Time
+	for _, item := range p.snapshot() {
+		if item != nil && item.PubDate != nil && item.PubDate.After(now) && (next.IsZero() || item.PubDate.Before(next)) {
+			next = item.PubDate.Time
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// snapshot returns the current items. The returned slice is never modified,
+// as AddItem only appends past its length and other changes replace it.
+func (p *Podcast) snapshot() []*Item {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.items[:len(p.items):len(p.items)]
+}
+
+// published returns the items without a pubDate or published at or before now.
+func published(items []*Item, now time.Time) []*Item {
+	visible := make([]*Item, 0, len(items))
+	for _, item := range items {
+		if item == nil || item.PubDate == nil || !item.PubDate.After(now) {
+			visible = append(visible, item)
+		}
+	}
+	return visible
+}
+
+// indexOf returns the index of the first item with given guid, or -1.
+func (p *Podcast) indexOf(guid string) int {
+	for i, item := range p.items {
+		if item != nil && item.GUID == guid {
+			return i
+		}
+	}
+	return -1
+}
+
+// feed creates a new feed for current podcast with given items.
+func (p *Podcast) feed(items []*Item, options ...func(f *Feed) error) (*Feed, error) {
 	feed := &Feed{
-		ItunesXMLNS:  itunesXMLNS,
-		ContentXMLNS: contentXMLNS,
-		Version:      rssVersion,
+		Version: rssVersion,
 		Channel: &Channel{
 			Title:       p.Title,
 			Description: p.Description,
 			Link:        p.Link,
 			Copyright:   p.Copyright,
 			Language:    p.Language,
-			Items:       p.items,
+			Items:       items,
 		},
 	}
 	err := feed.SetOptions(options...)
+	if feed.Channel.LastBuildDate == nil {
+		feed.Channel.LastBuildDate = latestPubDate(feed.Channel.Items)
+	}
 	return feed, err
 }
+
+// latestPubDate returns the pubDate of the newest item, or nil if no item has one.
+func latestPubDate(items []*Item) *PubDate {
+	var latest *PubDate
+	for _, item := range items {
+		if item != nil && item.PubDate != nil && (latest == nil || item.PubDate.After(latest.Time)) {
+			latest = item.PubDate
+		}
+	}
+	return latest
+}
+
+// cloneItem returns a deep copy of given item.
+func cloneItem(item *Item) *Item {
+	if item == nil {
+		return nil
+	}
+	clone := *item
+	if item.PubDate != nil {
+		pubDate := *item.PubDate
+		clone.PubDate = &pubDate
+	}
+	clone.Description = cloneCDATA(item.Description)
+	clone.ContentEncoded = cloneCDATA(item.ContentEncoded)
+	clone.Summary = cloneCDATA(item.Summary)
+	if item.Duration != nil {
+		duration := *item.Duration
+		clone.Duration = &duration
+	}
+	if item.Enclosure != nil {
+		enclosure := *item.Enclosure
+		clone.Enclosure = &enclosure
+	}
+	if item.Image != nil {
+		image := *item.Image
+		clone.Image = &image
+	}
+	if item.Transcripts != nil {
+		clone.Transcripts = make([]*PodcastTranscript, len(item.Transcripts))
+		for i, transcript := range item.Transcripts {
+			if transcript != nil {
+				copied := *transcript
+				clone.Transcripts[i] = &copied
+			}
+		}
+	}
+	if item.Chapters != nil {
+		chapters := *item.Chapters
+		clone.Chapters = &chapters
+	}
+	if item.SimpleChapters != nil {
+		simple := *item.SimpleChapters
+		simple.Chapters = make([]*Chapter, len(item.SimpleChapters.Chapters))
+		for i, chapter := range item.SimpleChapters.Chapters {
+			if chapter != nil {
+				copied := *chapter
+				simple.Chapters[i] = &copied
+			}
+		}
+		clone.SimpleChapters = &simple
+	}
+	clone.Extensions = cloneExtensions(item.Extensions)
+	clone.ExtensionAttrs = cloneAttrs(item.ExtensionAttrs)
+	return &clone
+}
+
+func cloneCDATA(text *CDATAText) *CDATAText {
+	if text == nil {
+		return nil
+	}
+	copied := *text
+	return &copied
+}
@@ -2,8 +2,11 @@ package podcasts
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -285,6 +288,113 @@ func TestContainsImageElement(t *testing.T) {
 	}
 }
 
+func TestContainsTypeElement(t *testing.T) {
+	podcast := &Podcast{}
+	data, err := getPodcastXML(podcast, Type(Serial))
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	want := fmt.Sprintf("<itunes:type>%v</itunes:type>", Serial)
+	if !strings.Contains(data, want) {
+		t.Errorf("expected %v to contain %v", data, want)
+	}
+}
+
+func TestContainsEpisodeElements(t *testing.T) {
+	podcast := &Podcast{}
+	podcast.AddItem(&Item{
+		Title:       "S2E3: The One With The Tags",
+		ItunesTitle: "The One With The Tags",
+		GUID:        "https://example.com/s2e3",
+		Season:      2,
+		Episode:     3,
+		EpisodeType: EpisodeBonus,
+	})
+	data, err := getPodcastXML(podcast)
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	for _, want := range []string{
+		"<itunes:title>The One With The Tags</itunes:title>",
+		"<itunes:season>2</itunes:season>",
+		"<itunes:episode>3</itunes:episode>",
+		"<itunes:episodeType>bonus</itunes:episodeType>",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("expected %v to contain %v", data, want)
+		}
+	}
+}
+
+func TestContainsRSSChannelElements(t *testing.T) {
+	podcast := &Podcast{}
+	data, err := getPodcastXML(podcast,
+		SelfLink("http://localhost/feed.xml"),
+		ManagingEditor("editor@localhost (Editor)"),
+		WebMaster("webmaster@localhost (Webmaster)"),
+		Published(time.Date(2015, time.January, 1, 0, 0, 0, 0, time.UTC)),
+		LastBuildDate(time.Date(2015, time.January, 2, 0, 0, 0, 0, time.UTC)),
+		Generator("podcasts"),
+		Docs("https://www.rssboard.org/rss-specification"),
+		TTL(time.Hour),
+		SkipHours(1, 2),
+		SkipDays(time.Sunday),
+	)
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	for _, want := range []string{
+		`xmlns:atom="http://www.w3.org/2005/Atom"`,
+		`<atom:link href="http://localhost/feed.xml" rel="self" type="application/rss+xml"></atom:link>`,
+		"<managingEditor>editor@localhost (Editor)</managingEditor>",
+		"<webMaster>webmaster@localhost (Webmaster)</webMaster>",
+		"<pubDate>Thu, 01 Jan 2015 00:00:00 +0000</pubDate>",
+		"<lastBuildDate>Fri, 02 Jan 2015 00:00:00 +0000</lastBuildDate>",
+		"<generator>podcasts</generator>",
+		"<docs>https://www.rssboard.org/rss-specification</docs>",
+		"<ttl>60</ttl>",
+		"<skipHours>\n      <hour>1</hour>\n      <hour>2</hour>\n    </skipHours>",
+		"<skipDays>\n      <day>Sunday</day>\n    </skipDays>",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("expected %v to contain %v", data, want)
+		}
+	}
+}
+
+func TestLastBuildDateFromNewestItem(t *testing.T) {
+	podcast := setupPodcast()
+	data, err := getPodcastXML(podcast)
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	want := "<lastBuildDate>Sat, 03 Jan 2015 00:00:00 +0000</lastBuildDate>"
+	if !strings.Contains(data, want) {
+		t.Errorf("expected %v to contain %v", data, want)
+	}
+
+	data, err = getPodcastXML(&Podcast{})
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if strings.Contains(data, "<lastBuildDate>") {
+		t.Errorf("expected %v not to contain lastBuildDate", data)
+	}
+}
+
+func TestContainsGUIDPermaLink(t *testing.T) {
+	podcast := &Podcast{}
+	podcast.AddItem(&Item{Title: "Episode", GUID: "episode-1", GUIDIsPermaLink: "false"})
+	data, err := getPodcastXML(podcast)
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	want := `<guid isPermaLink="false">episode-1</guid>`
+	if !strings.Contains(data, want) {
+		t.Errorf("expected %v to contain %v", data, want)
+	}
+}
+
 func TestContainsItemElements(t *testing.T) {
 	podcast := setupPodcast()
 	feed, err := podcast.Feed()
@@ -317,6 +427,142 @@ func TestPodcastFeedWrite(t *testing.T) {
 	}
 }
 
+func TestRemoveItem(t *testing.T) {
+	p := setupPodcast()
+	feed, _ := p.Feed()
+	if !p.RemoveItem(validItems[1].guid) {
+		t.Fatal("expected item to be removed")
+	}
+	if p.RemoveItem(validItems[1].guid) {
+		t.Error("expected item to be removed only once")
+	}
+	if got := titles(p.Items()); got != "Item 1, Item 3" {
+		t.Errorf("expected %v got %v", "Item 1, Item 3", got)
+	}
+	if got := titles(feed.Channel.Items); got != "Item 1, Item 2, Item 3" {
+		t.Errorf("expected earlier feed to keep its items got %v", got)
+	}
+}
+
+func TestUpdateItem(t *testing.T) {
+	p := setupPodcast()
+	feed, _ := p.Feed()
+	err := p.UpdateItem(validItems[0].guid, func(item *Item) error {
+		item.Title = "Item 1 (updated)"
+		item.Enclosure.URL = "http://www.example-podcast.com/my-podcast/1/episode-one-fixed"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	items := p.Items()
+	if items[0].Title != "Item 1 (updated)" || items[0].Enclosure.URL != "http://www.example-podcast.com/my-podcast/1/episode-one-fixed" {
+		t.Errorf("unexpected item %+v", items[0])
+	}
+	if old := feed.Channel.Items[0]; old.Title != "Item 1" || old.Enclosure.URL != validItems[0].enclosureURL {
+		t.Errorf("expected earlier feed to keep its item got %+v", old)
+	}
+
+	failure := errors.New("failure")
+	err = p.UpdateItem(validItems[1].guid, func(item *Item) error {
+		item.Title = "discarded"
+		return failure
+	})
+	if err != failure {
+		t.Errorf("expected %v got %v", failure, err)
+	}
+	if got := p.Items()[1].Title; got != "Item 2" {
+		t.Errorf("expected %v got %v", "Item 2", got)
+	}
+	if err := p.UpdateItem("missing", func(item *Item) error { return nil }); err != ErrItemNotFound {
+		t.Errorf("expected %v got %v", ErrItemNotFound, err)
+	}
+}
+
+func TestItemsCopy(t *testing.T) {
+	p := setupPodcast()
+	items := p.Items()
+	items[0].Title = "changed"
+	items[0].Enclosure.URL = "changed"
+	items[1] = nil
+	if got := p.Items(); got[0].Title != "Item 1" || got[0].Enclosure.URL != validItems[0].enclosureURL || got[1] == nil {
+		t.Errorf("expected podcast items to be unchanged got %+v", got)
+	}
+}
+
+func TestPodcastConcurrency(t *testing.T) {
+	p := setupPodcast()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				feed, err := p.Feed()
+				if err != nil {
+					t.Errorf("unexpected error %v", err)
+					return
+				}
+				if err := feed.Write(io.Discard); err != nil {
+					t.Errorf("unexpected error %v", err)
+					return
+				}
+			}
+		}()
+	}
+	for j := 0; j < 50; j++ {
+		guid := fmt.Sprintf("http://www.example-podcast.com/my-podcast/extra/%d", j)
+		p.AddItem(&Item{Title: "Extra", GUID: guid})
+		p.UpdateItem(guid, func(item *Item) error {
+			item.Title = "Updated"
+			return nil
+		})
+		if j%2 == 0 {
+			p.RemoveItem(guid)
+		}
+	}
+	wg.Wait()
+	if got := len(p.Items()); got != len(validItems)+25 {
+		t.Errorf("expected %d items got %d", len(validItems)+25, got)
+	}
+}
+
+func TestFeedAt(t *testing.T) {
+	p := setupPodcast()
+	p.AddItem(&Item{Title: "Undated", GUID: "http://www.example-podcast.com/my-podcast/undated"})
+	now := validItems[1].pubDate
+	feed, err := p.FeedAt(now)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := titles(feed.Channel.Items); got != "Item 1, Item 2, Undated" {
+		t.Errorf("expected %v got %v", "Item 1, Item 2, Undated", got)
+	}
+	if !feed.Channel.LastBuildDate.Equal(now) {
+		t.Errorf("expected %v got %v", now, feed.Channel.LastBuildDate.Time)
+	}
+
+	p.AddItem(&Item{Title: "Scheduled", GUID: "http://www.example-podcast.com/my-podcast/scheduled", PubDate: NewPubDate(time.Now().Add(time.Hour))})
+	feed, err = p.Feed()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := titles(feed.Channel.Items); strings.Contains(got, "Scheduled") {
+		t.Errorf("expected scheduled item to be left out got %v", got)
+	}
+}
+
+func TestNextPublication(t *testing.T) {
+	p := setupPodcast()
+	next, ok := p.NextPublication(validItems[0].pubDate)
+	if !ok || !next.Equal(validItems[1].pubDate) {
+		t.Errorf("expected %v got %v %v", validItems[1].pubDate, next, ok)
+	}
+	if _, ok := p.NextPublication(validItems[len(validItems)-1].pubDate); ok {
+		t.Error("expected no scheduled item")
+	}
+}
+
 func getPodcastXML(p *Podcast, options ...func(f *Feed) error) (string, error) {
 	feed, err := p.Feed(options...)
 	if err != nil {
@@ -0,0 +1,48 @@
+package podcasts
+
+import "encoding/xml"
+
+const podcastXMLNS = "https://podcastindex.org/namespace/1.0"
+
+// PodcastLocked represents podcast:locked of given channel.
+type PodcastLocked struct {
+	XMLName xml.Name `xml:"podcast:locked"`
+	Owner   string   `xml:"owner,attr,omitempty"`
+	Value   string   `xml:",chardata"`
+}
+
+// PodcastFunding represents podcast:funding of given channel.
+type PodcastFunding struct {
+	XMLName xml.Name `xml:"podcast:funding"`
+	URL     string   `xml:"url,attr"`
+	Value   string   `xml:",chardata"`
+}
+
+// PodcastTranscript represents podcast:transcript of given item.
+type PodcastTranscript struct {
+	XMLName  xml.Name `xml:"podcast:transcript"`
+	URL      string   `xml:"url,attr"`
+	Type     string   `xml:"type,attr"`
+	Language string   `xml:"language,attr,omitempty"`
+	Rel      string   `xml:"rel,attr,omitempty"`
+}
+
+// PodcastChapters represents podcast:chapters of given item.
+type PodcastChapters struct {
+	XMLName xml.Name `xml:"podcast:chapters"`
+	URL     string   `xml:"url,attr"`
+	Type    string   `xml:"type,attr"`
+}
+
+// usesPodcastNamespace reports whether any podcast: element is set on the channel or its items.
+func usesPodcastNamespace(c *Channel) bool {
+	if c.Locked != nil || len(c.Funding) > 0 {
+		return true
+	}
+	for _, item := range c.Items {
+		if item != nil && (len(item.Transcripts) > 0 || item.Chapters != nil) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package podcasts
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPodcastNamespaceOnlyWhenUsed(t *testing.T) {
+	data, err := getPodcastXML(setupPodcast())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if strings.Contains(data, podcastXMLNS) {
+		t.Errorf("expected %v not to declare %v", data, podcastXMLNS)
+	}
+
+	data, err = getPodcastXML(setupPodcast(), Locked("owner@example.com"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := `<rss xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd" xmlns:content="http://purl.org/rss/1.0/modules/content/" ` +
+		`xmlns:podcast="https://podcastindex.org/namespace/1.0" version="2.0">`
+	if !strings.Contains(data, want) {
+		t.Errorf("expected %v to contain %v", data, want)
+	}
+}
+
+func TestContainsPodcastElements(t *testing.T) {
+	podcast := &Podcast{}
+	podcast.AddItem(&Item{
+		Title: "Episode",
+		GUID:  "https://example.com/1",
+		Transcripts: []*PodcastTranscript{
+			{URL: "https://example.com/1.vtt", Type: "text/vtt", Language: "en", Rel: "captions"},
+			{URL: "https://example.com/1.json", Type: "application/json"},
+		},
+		Chapters: &PodcastChapters{URL: "https://example.com/1/chapters.json", Type: "application/json+chapters"},
+	})
+	data, err := getPodcastXML(podcast, Locked("owner@example.com"), Funding("https://example.com/donate", "Support us"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, want := range []string{
+		`xmlns:podcast="https://podcastindex.org/namespace/1.0"`,
+		`<podcast:locked owner="owner@example.com">yes</podcast:locked>`,
+		`<podcast:funding url="https://example.com/donate">Support us</podcast:funding>`,
+		`<podcast:transcript url="https://example.com/1.vtt" type="text/vtt" language="en" rel="captions"></podcast:transcript>`,
+		`<podcast:transcript url="https://example.com/1.json" type="application/json"></podcast:transcript>`,
+		`<podcast:chapters url="https://example.com/1/chapters.json" type="application/json+chapters"></podcast:chapters>`,
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("expected %v to contain %v", data, want)
+		}
+	}
+
+	parsed, err := Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got, err := parsed.XML(); err != nil || got != data {
+		t.Errorf("expected %v got %v (%v)", data, got, err)
+	}
+	item := parsed.Channel.Items[0]
+	if len(item.Transcripts) != 2 || item.Transcripts[0].Rel != "captions" {
+		t.Errorf("unexpected transcripts %+v", item.Transcripts)
+	}
+	if parsed.Channel.Locked.Owner != "owner@example.com" {
+		t.Errorf("unexpected locked %+v", parsed.Channel.Locked)
+	}
+}
+
+func TestValidatePodcastElements(t *testing.T) {
+	feed := setupValidFeed(t)
+	feed.Channel.Locked = &PodcastLocked{Value: "maybe"}
+	feed.Channel.Funding = []*PodcastFunding{{URL: "donate"}}
+	feed.Channel.Items[0].Transcripts = []*PodcastTranscript{{URL: "https://example.com/1.vtt"}}
+	feed.Channel.Items[0].Chapters = &PodcastChapters{Type: "application/json+chapters"}
+
+	problems := feed.Validate()
+	for _, field := range []string{
+		"Channel.Locked.Value",
+		"Channel.Funding[0].URL",
+		"Channel.Items[0].Transcripts[0].Type",
+		"Channel.Items[0].Chapters.URL",
+	} {
+		if !hasProblem(problems, field, SeverityError) {
+			t.Errorf("expected error for %v in %v", field, problems)
+		}
+	}
+}
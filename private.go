@@ -0,0 +1,353 @@
+package podcasts
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidToken represents a error returned for malformed or forged tokens.
+	ErrInvalidToken = errors.New("podcasts: invalid token")
+
+	// ErrExpiredToken represents a error returned for expired media tokens.
+	ErrExpiredToken = errors.New("podcasts: expired token")
+
+	// ErrRevokedToken represents a error returned for tokens of revoked subscribers.
+	ErrRevokedToken = errors.New("podcasts: revoked token")
+
+	// ErrInvalidKey represents a error returned when signing or verifying
+	// tokens with a key shorter than 32 bytes.
+	ErrInvalidKey = errors.New("podcasts: invalid signing key")
+)
+
+const (
+	// TokenParam is the query parameter carrying subscriber and media tokens.
+	TokenParam = "token"
+
+	// defaultMediaTTL is the validity of media tokens when Signer.MediaTTL is not set.
+	defaultMediaTTL = 24 * time.Hour
+
+	// macSize is the number of bytes of the HMAC kept in tokens.
+	macSize = 16
+
+	// minKeySize is the minimum number of bytes of Signer.Key.
+	minKeySize = 32
+)
+
+// Signer signs and verifies the HMAC-SHA256 tokens of private feeds.
+//
+// Each subscriber gets a feed url carrying a subscriber token, which never
+// expires but stops working once the subscriber is revoked. Enclosure urls of
+// the private feed carry media tokens bound to the subscriber and the media
+// path, which expire after MediaTTL.
+//
+// Tokens are neither signed nor verified with a Key shorter than 32 bytes,
+// so that a zero Signer cannot be used to forge them.
+type Signer struct {
+	// Key is the secret HMAC key, at least 32 random bytes.
+	Key []byte
+	// MediaTTL is how long media tokens are valid for, 24 hours if zero.
+	MediaTTL time.Duration
+	// Revocations lists the revoked subscribers, if any.
+	Revocations *RevocationList
+	// Now returns the current time, time.Now if nil.
+	Now func() time.Time
+}
+
+// NewSigner returns a new Signer with given key, which must be at least 32 bytes.
+func NewSigner(key []byte) (*Signer, error) {
+	if len(key) < minKeySize {
+		return nil, ErrInvalidKey
+	}
+	return &Signer{Key: key}, nil
+}
+
+// SubscriberToken returns the feed token of given subscriber, or an empty
+// string if the key is shorter than 32 bytes.
+func (s *Signer) SubscriberToken(subscriber string) string {
+	if s.checkKey() != nil {
+		return ""
+	}
+	return encodeToken(subscriber) + "." + encodeToken(string(s.mac("feed", subscriber)))
+}
+
+// FeedURL returns the private feed url of given subscriber.
+func (s *Signer) FeedURL(feedURL, subscriber string) (string, error) {
+	if err := s.checkKey(); err != nil {
+		return "", err
+	}
+	return withToken(feedURL, s.SubscriberToken(subscriber))
+}
+
+// VerifySubscriber returns the subscriber of given feed token.
+func (s *Signer) VerifySubscriber(token string) (string, error) {
+	if err := s.checkKey(); err != nil {
+		return "", err
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return "", ErrInvalidToken
+	}
+	subscriber, err := decodeToken(parts[0])
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	if err := s.verifyMAC(parts[1], "feed", subscriber); err != nil {
+		return "", err
+	}
+	if s.Revocations.Revoked(subscriber) {
+		return "", ErrRevokedToken
+	}
+	return subscriber, nil
+}
+
+// MediaURL returns the media url signed for given subscriber. Expiry times are
+// rounded to half of MediaTTL, so that the private feed only changes, and
+// needs to be downloaded again, twice per MediaTTL.
+func (s *Signer) MediaURL(mediaURL, subscriber string) (string, error) {
+	if err := s.checkKey(); err != nil {
+		return "", err
+	}
+	u, err := url.Parse(mediaURL)
+	if err != nil {
+		return "", err
+	}
+	ttl := s.mediaTTL()
+	window := int64(ttl / time.Second / 2)
+	if window < 1 {
+		window = 1
+	}
+	expires := s.now().Unix()/window*window + int64(ttl/time.Second)
+	expiry := strconv.FormatInt(expires, 36)
+	mac := s.mac("media", subscriber, expiry, u.EscapedPath())
+	return withToken(mediaURL, encodeToken(subscriber)+"."+expiry+"."+encodeToken(string(mac)))
+}
+
+// VerifyMedia returns the subscriber of the media token of given request,
+// checking that it was signed for the requested path and has not expired.
+func (s *Signer) VerifyMedia(r *http.Request) (string, error) {
+	if err := s.checkKey(); err != nil {
+		return "", err
+	}
+	parts := strings.Split(r.URL.Query().Get(TokenParam), ".")
+	if len(parts) != 3 {
+		return "", ErrInvalidToken
+	}
+	subscriber, err := decodeToken(parts[0])
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	expires, err := strconv.ParseInt(parts[1], 36, 64)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	if err := s.verifyMAC(parts[2], "media", subscriber, parts[1], r.URL.EscapedPath()); err != nil {
+		return "", err
+	}
+	if s.now().Unix() >= expires {
+		return "", ErrExpiredToken
+	}
+	if s.Revocations.Revoked(subscriber) {
+		return "", ErrRevokedToken
+	}
+	return subscriber, nil
+}
+
+// MediaHandler returns a handler serving media with next, only for requests
+// with a valid media token. Every request is forbidden if the key is shorter
+// than 32 bytes.
+func (s *Signer) MediaHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := s.VerifyMedia(r); err != nil {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Subscriber turns given feed into the private feed of the subscriber: each
+// enclosure url is signed with a media token, and the self link gets the
+// subscriber token. Items are copied, so the podcast itself is not modified.
+func (s *Signer) Subscriber(subscriber string) func(f *Feed) error {
+	return func(f *Feed) error {
+		if err := s.checkKey(); err != nil {
+			return err
+		}
+		items := make([]*Item, len(f.Channel.Items))
+		for i, item := range f.Channel.Items {
+			if item == nil || item.Enclosure == nil {
+				items[i] = item
+				continue
+			}
+			signed, err := s.MediaURL(item.Enclosure.URL, subscriber)
+			if err != nil {
+				return err
+			}
+			copied := *item
+			enclosure := *item.Enclosure
+			enclosure.URL = signed
+			copied.Enclosure = &enclosure
+			items[i] = &copied
+		}
+		f.Channel.Items = items
+
+		links := make([]*AtomLink, len(f.Channel.AtomLinks))
+		for i, link := range f.Channel.AtomLinks {
+			links[i] = link
+			if link != nil && link.Rel == "self" {
+				href, err := s.FeedURL(link.Href, subscriber)
+				if err != nil {
+					return err
+				}
+				copied := *link
+				copied.Href = href
+				links[i] = &copied
+			}
+		}
+		f.Channel.AtomLinks = links
+		return nil
+	}
+}
+
+// checkKey returns ErrInvalidKey if the signer is nil or its key is shorter than 32 bytes.
+func (s *Signer) checkKey() error {
+	if s == nil || len(s.Key) < minKeySize {
+		return ErrInvalidKey
+	}
+	return nil
+}
+
+func (s *Signer) mac(kind string, fields ...string) []byte {
+	h := hmac.New(sha256.New, s.Key)
+	h.Write([]byte(kind))
+	for _, field := range fields {
+		// Fields are length prefixed so that they cannot run into each other.
+		h.Write([]byte(":" + strconv.Itoa(len(field)) + ":" + field))
+	}
+	return h.Sum(nil)[:macSize]
+}
+
+func (s *Signer) verifyMAC(encoded, kind string, fields ...string) error {
+	mac, err := decodeToken(encoded)
+	if err != nil || !hmac.Equal([]byte(mac), s.mac(kind, fields...)) {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+func (s *Signer) mediaTTL() time.Duration {
+	if s.MediaTTL <= 0 {
+		return defaultMediaTTL
+	}
+	return s.MediaTTL
+}
+
+func (s *Signer) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
+	}
+	return s.Now()
+}
+
+func encodeToken(s string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+func decodeToken(s string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	return string(b), err
+}
+
+// withToken returns rawURL with the token query parameter set.
+func withToken(rawURL, token string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set(TokenParam, token)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// RevocationList records revoked subscribers. It is safe for concurrent use.
+type RevocationList struct {
+	mu      sync.RWMutex
+	revoked map[string]bool
+}
+
+// Revoke revokes the feed and media tokens of given subscribers.
+func (l *RevocationList) Revoke(subscribers ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.revoked == nil {
+		l.revoked = make(map[string]bool)
+	}
+	for _, subscriber := range subscribers {
+		l.revoked[subscriber] = true
+	}
+}
+
+// Restore reinstates the tokens of given subscribers.
+func (l *RevocationList) Restore(subscribers ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, subscriber := range subscribers {
+		delete(l.revoked, subscriber)
+	}
+}
+
+// Revoked reports whether the tokens of given subscriber are revoked.
+// A nil list revokes nobody.
+func (l *RevocationList) Revoked(subscriber string) bool {
+	if l == nil {
+		return false
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.revoked[subscriber]
+}
+
+// PrivateHandler serves the private feeds of a podcast to subscribers,
+// identified by the subscriber token in the url, with the ETag and gzip
+// support of Handler. Requests without a valid token are forbidden.
+//
+// Responses carry no Last-Modified date, since the media tokens of the feed
+// change while its items do not, and clients must revalidate with the ETag.
+// Every subscriber gets a different feed, so gzipped feeds are not cached.
+type PrivateHandler struct {
+	// Podcast is the podcast whose feeds are served.
+	Podcast *Podcast
+	// Options customise the served feeds, as in Podcast.Feed.
+	Options []func(f *Feed) error
+	// Signer verifies subscriber tokens and signs media urls. Every request
+	// fails with 500 Internal Server Error if it is nil or its key is invalid.
+	Signer *Signer
+}
+
+// ServeHTTP serves the private feed of the subscriber in response to GET and HEAD requests.
+func (h *PrivateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	subscriber, err := h.Signer.VerifySubscriber(r.URL.Query().Get(TokenParam))
+	if errors.Is(err, ErrInvalidKey) {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+	options := make([]func(f *Feed) error, 0, len(h.Options)+1)
+	options = append(options, h.Options...)
+	options = append(options, h.Signer.Subscriber(subscriber))
+	w.Header().Set("Cache-Control", "private")
+	serveFeed(w, r, nil, h.Podcast, options, false)
+}
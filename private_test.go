@@ -0,0 +1,274 @@
+package podcasts
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSignerNow = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func setupSigner() *Signer {
+	return &Signer{
+		Key:         []byte("0123456789abcdef0123456789abcdef"),
+		Revocations: &RevocationList{},
+		Now:         func() time.Time { return testSignerNow },
+	}
+}
+
+func setupPrivateHandler() *PrivateHandler {
+	podcast := &Podcast{Title: "Private Podcast", Link: "https://example.com"}
+	podcast.AddItem(&Item{
+		Title:     "Episode 1",
+		GUID:      "https://example.com/1",
+		PubDate:   NewPubDate(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)),
+		Enclosure: &Enclosure{URL: "https://cdn.example.com/media/1.mp3", Length: "100", Type: MediaTypeMP3},
+	})
+	return &PrivateHandler{
+		Podcast: podcast,
+		Options: []func(f *Feed) error{SelfLink("https://example.com/private.xml")},
+		Signer:  setupSigner(),
+	}
+}
+
+func tokenOf(t *testing.T, rawURL string) string {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return u.Query().Get(TokenParam)
+}
+
+func TestSubscriberToken(t *testing.T) {
+	s := setupSigner()
+	feedURL, err := s.FeedURL("https://example.com/private.xml?format=rss", "alice")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !strings.HasPrefix(feedURL, "https://example.com/private.xml?") || !strings.Contains(feedURL, "format=rss") {
+		t.Errorf("unexpected feed url %v", feedURL)
+	}
+	token := tokenOf(t, feedURL)
+	if token != s.SubscriberToken("alice") {
+		t.Errorf("expected %v got %v", s.SubscriberToken("alice"), token)
+	}
+	if subscriber, err := s.VerifySubscriber(token); err != nil || subscriber != "alice" {
+		t.Errorf("expected alice got %v %v", subscriber, err)
+	}
+
+	forged := strings.Replace(token, encodeToken("alice"), encodeToken("mallory"), 1)
+	other := &Signer{Key: []byte("fedcba9876543210fedcba9876543210")}
+	for _, invalid := range []string{"", "alice", forged, token + ".x", other.SubscriberToken("alice")} {
+		if _, err := s.VerifySubscriber(invalid); err != ErrInvalidToken {
+			t.Errorf("%q: expected %v got %v", invalid, ErrInvalidToken, err)
+		}
+	}
+
+	s.Revocations.Revoke("alice")
+	if _, err := s.VerifySubscriber(token); err != ErrRevokedToken {
+		t.Errorf("expected %v got %v", ErrRevokedToken, err)
+	}
+	s.Revocations.Restore("alice")
+	if _, err := s.VerifySubscriber(token); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestMediaToken(t *testing.T) {
+	s := setupSigner()
+	mediaURL, err := s.MediaURL("https://cdn.example.com/media/1.mp3", "alice")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	r := httptest.NewRequest(http.MethodGet, mediaURL, nil)
+	if subscriber, err := s.VerifyMedia(r); err != nil || subscriber != "alice" {
+		t.Errorf("expected alice got %v %v", subscriber, err)
+	}
+
+	// Tokens are stable within half of the TTL, so the feed does not change on every request.
+	s.Now = func() time.Time { return testSignerNow.Add(time.Minute) }
+	if again, _ := s.MediaURL("https://cdn.example.com/media/1.mp3", "alice"); again != mediaURL {
+		t.Errorf("expected %v got %v", mediaURL, again)
+	}
+
+	other := httptest.NewRequest(http.MethodGet, strings.Replace(mediaURL, "1.mp3", "2.mp3", 1), nil)
+	if _, err := s.VerifyMedia(other); err != ErrInvalidToken {
+		t.Errorf("expected %v got %v", ErrInvalidToken, err)
+	}
+	missing := httptest.NewRequest(http.MethodGet, "https://cdn.example.com/media/1.mp3", nil)
+	if _, err := s.VerifyMedia(missing); err != ErrInvalidToken {
+		t.Errorf("expected %v got %v", ErrInvalidToken, err)
+	}
+
+	s.Revocations.Revoke("alice")
+	if _, err := s.VerifyMedia(r); err != ErrRevokedToken {
+		t.Errorf("expected %v got %v", ErrRevokedToken, err)
+	}
+
+	s.Now = func() time.Time { return testSignerNow.Add(2 * defaultMediaTTL) }
+	if _, err := s.VerifyMedia(r); err != ErrExpiredToken {
+		t.Errorf("expected %v got %v", ErrExpiredToken, err)
+	}
+}
+
+func TestMediaHandler(t *testing.T) {
+	s := setupSigner()
+	h := s.MediaHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("audio"))
+	}))
+	mediaURL, _ := s.MediaURL("https://cdn.example.com/media/1.mp3", "alice")
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, mediaURL, nil))
+	if w.Code != http.StatusOK || w.Body.String() != "audio" {
+		t.Errorf("unexpected response %v %v", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "https://cdn.example.com/media/1.mp3", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected %v got %v", http.StatusForbidden, w.Code)
+	}
+}
+
+func TestPrivateHandler(t *testing.T) {
+	h := setupPrivateHandler()
+	feedURL, _ := h.Signer.FeedURL("https://example.com/private.xml", "alice")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, feedURL, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %v got %v", http.StatusOK, w.Code)
+	}
+	if got := w.Header().Get("Cache-Control"); got != "private" {
+		t.Errorf("expected %v got %v", "private", got)
+	}
+	if got := w.Header().Get("Last-Modified"); got != "" {
+		t.Errorf("expected no Last-Modified got %v", got)
+	}
+	feed, err := Parse(strings.NewReader(w.Body.String()))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	enclosure := feed.Channel.Items[0].Enclosure.URL
+	r := httptest.NewRequest(http.MethodGet, enclosure, nil)
+	if subscriber, err := h.Signer.VerifyMedia(r); err != nil || subscriber != "alice" {
+		t.Errorf("expected alice got %v %v", subscriber, err)
+	}
+	if self := links(feed.Channel)["self"]; self != feedURL {
+		t.Errorf("expected %v got %v", feedURL, self)
+	}
+
+	// The public feed of the same podcast is left untouched.
+	public, err := h.Podcast.Feed()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := public.Channel.Items[0].Enclosure.URL; got != "https://cdn.example.com/media/1.mp3" {
+		t.Errorf("expected unsigned enclosure got %v", got)
+	}
+
+	for _, target := range []string{"https://example.com/private.xml", "https://example.com/private.xml?token=invalid"} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%v: expected %v got %v", target, http.StatusForbidden, w.Code)
+		}
+	}
+
+	h.Signer.Revocations.Revoke("alice")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, feedURL, nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected %v got %v", http.StatusForbidden, w.Code)
+	}
+}
+
+func TestPrivateHandlerCaching(t *testing.T) {
+	h := setupPrivateHandler()
+	feedURLs := make(map[string]string)
+	for _, subscriber := range []string{"alice", "bob"} {
+		feedURL, err := h.Signer.FeedURL("https://example.com/private.xml", subscriber)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		feedURLs[subscriber] = feedURL
+	}
+	get := func(subscriber string, header http.Header) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, feedURLs[subscriber], nil)
+		r.Header = header
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	// Media tokens change while the items do not, so dates must not revalidate the feed.
+	since := http.Header{"If-Modified-Since": {time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat)}}
+	if w := get("alice", since); w.Code != http.StatusOK {
+		t.Errorf("expected %v got %v", http.StatusOK, w.Code)
+	}
+
+	gzipped := http.Header{"Accept-Encoding": {"gzip"}}
+	alice, bob := get("alice", gzipped), get("bob", gzipped)
+	if alice.Header().Get("Content-Encoding") != "gzip" || bytes.Equal(alice.Body.Bytes(), bob.Body.Bytes()) {
+		t.Errorf("expected different gzipped feeds for each subscriber")
+	}
+	match := http.Header{"Accept-Encoding": {"gzip"}, "If-None-Match": {alice.Header().Get("ETag")}}
+	if w := get("alice", match); w.Code != http.StatusNotModified {
+		t.Errorf("expected %v got %v", http.StatusNotModified, w.Code)
+	}
+
+	h.Signer = nil
+	if w := get("alice", nil); w.Code != http.StatusInternalServerError {
+		t.Errorf("expected %v got %v", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestSignerKey(t *testing.T) {
+	if _, err := NewSigner([]byte("short key")); err != ErrInvalidKey {
+		t.Errorf("expected %v got %v", ErrInvalidKey, err)
+	}
+	signer, err := NewSigner([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if signer.SubscriberToken("alice") == "" {
+		t.Error("expected subscriber token")
+	}
+
+	for name, s := range map[string]*Signer{"Zero": {}, "Short": {Key: []byte("0123456789abcdef")}} {
+		t.Run(name, func(t *testing.T) {
+			if token := s.SubscriberToken("alice"); token != "" {
+				t.Errorf("expected empty token got %v", token)
+			}
+			if _, err := s.FeedURL("https://example.com/private.xml", "alice"); err != ErrInvalidKey {
+				t.Errorf("expected %v got %v", ErrInvalidKey, err)
+			}
+			if _, err := s.MediaURL("https://cdn.example.com/media/1.mp3", "alice"); err != ErrInvalidKey {
+				t.Errorf("expected %v got %v", ErrInvalidKey, err)
+			}
+			forged := encodeToken("alice") + "." + encodeToken(string(s.mac("feed", "alice")))
+			if _, err := s.VerifySubscriber(forged); err != ErrInvalidKey {
+				t.Errorf("expected %v got %v", ErrInvalidKey, err)
+			}
+			r := httptest.NewRequest(http.MethodGet, "https://cdn.example.com/media/1.mp3", nil)
+			if _, err := s.VerifyMedia(r); err != ErrInvalidKey {
+				t.Errorf("expected %v got %v", ErrInvalidKey, err)
+			}
+			served := false
+			w := httptest.NewRecorder()
+			s.MediaHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { served = true })).ServeHTTP(w, r)
+			if served || w.Code != http.StatusForbidden {
+				t.Errorf("expected %v got %v", http.StatusForbidden, w.Code)
+			}
+			feed := &Feed{Channel: &Channel{}}
+			if err := feed.SetOptions(s.Subscriber("alice")); err != ErrInvalidKey {
+				t.Errorf("expected %v got %v", ErrInvalidKey, err)
+			}
+		})
+	}
+}
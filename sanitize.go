@@ -0,0 +1,478 @@
+package podcasts
+
+import (
+	"bytes"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxDescriptionLength is the number of characters of a description or
+// summary shown by Apple Podcasts.
+const maxDescriptionLength = 4000
+
+// allowedTags lists the HTML tags accepted by podcast apps, kept by SanitizeHTML.
+var allowedTags = map[string]bool{
+	"p": true, "br": true, "a": true, "ul": true, "ol": true, "li": true,
+	"b": true, "strong": true, "i": true, "em": true,
+}
+
+// paragraphTags lists the block tags written as p, so that their text stays
+// in separate paragraphs.
+var paragraphTags = map[string]bool{
+	"div": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"blockquote": true, "pre": true, "section": true, "article": true, "header": true,
+	"footer": true, "address": true, "figure": true, "figcaption": true, "table": true,
+	"tr": true, "dl": true, "dt": true, "dd": true,
+}
+
+// droppedTags lists the tags removed along with their content.
+var droppedTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"noscript": true, "template": true, "textarea": true, "select": true,
+	"head": true, "title": true, "svg": true, "math": true,
+}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+)
+
+// SanitizeHTML returns the HTML subset accepted by podcast apps: p, br, ul,
+// ol, li, b, strong, i, em, and a with an absolute http, https or mailto
+// href. Headings and other block tags become paragraphs, scripts, styles and
+// embedded content are removed with their content, and every other tag and
+// attribute is removed, keeping its text. Tags are balanced.
+func SanitizeHTML(s string) string {
+	var z htmlSanitizer
+	for _, t := range visibleTokens(s) {
+		name := t.name
+		if paragraphTags[name] {
+			name = "p"
+		}
+		switch t.kind {
+		case textToken:
+			z.b.WriteString(textEscaper.Replace(t.data))
+		case startTagToken:
+			z.startTag(t, name)
+		case endTagToken:
+			if i := indexOfTag(z.open, name); i >= 0 {
+				z.open = closeTags(&z.b, z.open, i)
+			}
+		}
+	}
+	closeTags(&z.b, z.open, 0)
+	return z.b.String()
+}
+
+// htmlSanitizer holds the HTML written by SanitizeHTML and its open elements.
+type htmlSanitizer struct {
+	b    bytes.Buffer
+	open []string
+}
+
+// startTag writes the start tag of given name, if it is allowed.
+func (z *htmlSanitizer) startTag(t htmlToken, name string) {
+	switch {
+	case !allowedTags[name]:
+		return
+	case name == "br":
+		z.b.WriteString("<br>")
+		return
+	}
+	z.endBlocks(name)
+	if name == "a" {
+		href, ok := safeHref(t.attr("href"))
+		if !ok {
+			return
+		}
+		z.b.WriteString(`<a href="` + attrEscaper.Replace(href) + `">`)
+	} else {
+		z.b.WriteString("<" + name + ">")
+	}
+	z.open = append(z.open, name)
+}
+
+// endBlocks closes the open elements that cannot contain an element of given name.
+func (z *htmlSanitizer) endBlocks(name string) {
+	switch name {
+	case "p", "ul", "ol":
+		// Paragraphs cannot contain blocks, the open one ends.
+		if i := indexOfTag(z.open, "p"); i >= 0 {
+			z.open = closeTags(&z.b, z.open, i)
+		}
+	case "li":
+		// A list item ends the previous item of the same list.
+		if i := indexOfTag(z.open, "li"); i >= 0 && i > indexOfTag(z.open, "ul") && i > indexOfTag(z.open, "ol") {
+			z.open = closeTags(&z.b, z.open, i)
+		}
+	}
+}
+
+// HTMLText returns the plain text of given HTML: paragraphs are separated by
+// a blank line, list items start with "- " or their number, and links are
+// followed by their url in parentheses when it differs from their text.
+func HTMLText(s string) string {
+	var x htmlTextWriter
+	for _, t := range visibleTokens(s) {
+		switch t.kind {
+		case textToken:
+			x.w.text(t.data)
+		case startTagToken:
+			x.startTag(t)
+		case endTagToken:
+			x.endTag(t)
+		}
+	}
+	return x.w.b.String()
+}
+
+// htmlTextWriter holds the text written by HTMLText, with the open lists and links.
+type htmlTextWriter struct {
+	w textWriter
+	// lists holds the number of the last item of each open ol, and -1 for each ul.
+	lists []int
+	// links holds the offset of the text and the href of each open link.
+	links []int
+	hrefs []string
+}
+
+func (x *htmlTextWriter) startTag(t htmlToken) {
+	switch {
+	case t.name == "br":
+		x.w.lineBreak(1)
+	case t.name == "p" || paragraphTags[t.name]:
+		x.w.lineBreak(2)
+	case t.name == "ul":
+		x.w.lineBreak(2)
+		x.lists = append(x.lists, -1)
+	case t.name == "ol":
+		x.w.lineBreak(2)
+		x.lists = append(x.lists, 0)
+	case t.name == "li":
+		x.w.lineBreak(1)
+		if n := len(x.lists) - 1; n >= 0 && x.lists[n] >= 0 {
+			x.lists[n]++
+			x.w.prefix(strconv.Itoa(x.lists[n]) + ". ")
+		} else {
+			x.w.prefix("- ")
+		}
+	case t.name == "a" && !t.selfClosing:
+		href, _ := safeHref(t.attr("href"))
+		x.links = append(x.links, x.w.b.Len())
+		x.hrefs = append(x.hrefs, href)
+	}
+}
+
+func (x *htmlTextWriter) endTag(t htmlToken) {
+	switch {
+	case t.name == "p" || paragraphTags[t.name]:
+		x.w.lineBreak(2)
+	case t.name == "ul" || t.name == "ol":
+		if len(x.lists) > 0 {
+			x.lists = x.lists[:len(x.lists)-1]
+		}
+		x.w.lineBreak(2)
+	case t.name == "a" && len(x.links) > 0:
+		n := len(x.links) - 1
+		linkText := strings.TrimSpace(x.w.b.String()[x.links[n]:])
+		href := x.hrefs[n]
+		x.links, x.hrefs = x.links[:n], x.hrefs[:n]
+		if href != "" && linkText != href && linkText != strings.TrimPrefix(href, "mailto:") {
+			x.w.text(" (" + href + ")")
+		}
+	}
+}
+
+// textWriter writes plain text, collapsing white space as HTML does.
+type textWriter struct {
+	b strings.Builder
+	// newlines is the number of pending line breaks.
+	newlines int
+	space    bool
+	// lineStart is true when nothing but a prefix was written on the line.
+	lineStart bool
+}
+
+func (w *textWriter) text(s string) {
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			w.space = true
+			continue
+		}
+		w.flush()
+		if w.space && !w.lineStart {
+			w.b.WriteByte(' ')
+		}
+		w.space, w.lineStart = false, false
+		w.b.WriteRune(r)
+	}
+}
+
+func (w *textWriter) prefix(s string) {
+	w.flush()
+	w.b.WriteString(s)
+	w.space, w.lineStart = false, true
+}
+
+func (w *textWriter) lineBreak(n int) {
+	if n > w.newlines {
+		w.newlines = n
+	}
+}
+
+// flush writes the pending line breaks, unless nothing was written yet.
+func (w *textWriter) flush() {
+	if w.newlines > 0 && w.b.Len() > 0 {
+		w.b.WriteString(strings.Repeat("\n", w.newlines))
+		w.space, w.lineStart = false, true
+	}
+	w.newlines = 0
+}
+
+// truncateText shortens text to at most max characters, cutting at a word
+// boundary when possible and ending with an ellipsis.
+func truncateText(text string, max int) string {
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	runes := []rune(text)[:max-1]
+	cut := len(runes)
+	for i := len(runes) - 1; i > len(runes)/2; i-- {
+		if unicode.IsSpace(runes[i]) {
+			cut = i
+			break
+		}
+	}
+	return strings.TrimRightFunc(string(runes[:cut]), unicode.IsSpace) + "…"
+}
+
+// safeHref returns href if it is an absolute http, https or mailto url.
+func safeHref(href string) (string, bool) {
+	href = strings.TrimSpace(href)
+	u, err := url.Parse(href)
+	if err != nil {
+		return "", false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return href, u.Host != ""
+	case "mailto":
+		return href, u.Opaque != ""
+	default:
+		return "", false
+	}
+}
+
+func indexOfTag(open []string, name string) int {
+	for i := len(open) - 1; i >= 0; i-- {
+		if open[i] == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// closeTags writes the end tags of open from the last one to open[i], and
+// returns the tags left open. Elements left empty are removed.
+func closeTags(b *bytes.Buffer, open []string, i int) []string {
+	for j := len(open) - 1; j >= i; j-- {
+		if start := "<" + open[j] + ">"; bytes.HasSuffix(b.Bytes(), []byte(start)) {
+			b.Truncate(b.Len() - len(start))
+			continue
+		}
+		b.WriteString("</" + open[j] + ">")
+	}
+	return open[:i]
+}
+
+type htmlTokenKind int
+
+const (
+	textToken htmlTokenKind = iota
+	startTagToken
+	endTagToken
+)
+
+// htmlToken represents text, with entities decoded, or a tag of a HTML document.
+type htmlToken struct {
+	kind        htmlTokenKind
+	data        string
+	name        string
+	attrs       []htmlAttr
+	selfClosing bool
+}
+
+type htmlAttr struct {
+	name, value string
+}
+
+func (t htmlToken) attr(name string) string {
+	for _, a := range t.attrs {
+		if a.name == name {
+			return a.value
+		}
+	}
+	return ""
+}
+
+// tokenizeHTML splits s into text and tags. Comments and doctypes are left
+// out, and a < that does not start a terminated tag, comment or doctype is text.
+func tokenizeHTML(s string) []htmlToken {
+	var tokens []htmlToken
+	text := 0
+	flush := func(end int) {
+		if end > text {
+			tokens = append(tokens, htmlToken{kind: textToken, data: html.UnescapeString(s[text:end])})
+		}
+	}
+	// Nothing after the last > can be terminated, which saves searching the
+	// rest of s again at every following <.
+	lastGT, lastComment := strings.LastIndexByte(s, '>'), strings.LastIndex(s, "-->")
+	for i := 0; i < lastGT; {
+		if s[i] != '<' {
+			i++
+			continue
+		}
+		next := s[i+1]
+		n := -1
+		switch {
+		case strings.HasPrefix(s[i:], "<!--"):
+			if i+4 <= lastComment {
+				n = 4 + strings.Index(s[i+4:], "-->") + 3
+				flush(i)
+			}
+		case next == '!' || next == '?':
+			if end := strings.IndexByte(s[i:], '>'); end >= 0 {
+				n = end + 1
+				flush(i)
+			}
+		case isASCIILetter(next) || (next == '/' && i+2 < len(s) && isASCIILetter(s[i+2])):
+			var t htmlToken
+			if t, n = parseTag(s[i:]); n >= 0 {
+				flush(i)
+				tokens = append(tokens, t)
+			}
+		}
+		if n < 0 {
+			// Unterminated comments, doctypes and tags are text.
+			i++
+			continue
+		}
+		i += n
+		text = i
+	}
+	flush(len(s))
+	return tokens
+}
+
+// visibleTokens returns the tokens of s, leaving out the dropped tags and their content.
+func visibleTokens(s string) []htmlToken {
+	var tokens []htmlToken
+	skip := ""
+	for _, t := range tokenizeHTML(s) {
+		switch {
+		case skip != "":
+			if t.kind == endTagToken && t.name == skip {
+				skip = ""
+			}
+		case t.kind == startTagToken && droppedTags[t.name]:
+			if !t.selfClosing {
+				skip = t.name
+			}
+		default:
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens
+}
+
+// parseTag parses the tag at the start of s, returning its length or -1 if it
+// is not terminated.
+func parseTag(s string) (htmlToken, int) {
+	t := htmlToken{kind: startTagToken}
+	i := 1
+	if s[i] == '/' {
+		t.kind = endTagToken
+		i++
+	}
+	start := i
+	i = scanTo(s, i, func(c byte) bool { return isTagSpace(c) || c == '/' || c == '>' })
+	t.name = strings.ToLower(s[start:i])
+	for i < len(s) {
+		switch {
+		case s[i] == '>':
+			return t, i + 1
+		case s[i] == '/':
+			t.selfClosing = true
+			i++
+		case isTagSpace(s[i]):
+			i++
+		default:
+			var attr htmlAttr
+			if attr, i = parseAttr(s, i); i < 0 {
+				return t, -1
+			}
+			t.selfClosing = false
+			t.attrs = append(t.attrs, attr)
+		}
+	}
+	return t, -1
+}
+
+// parseAttr parses the attribute at s[i], returning the index following it or
+// -1 if its quoted value is not terminated.
+func parseAttr(s string, i int) (htmlAttr, int) {
+	start := i
+	i = scanTo(s, i, func(c byte) bool { return isTagSpace(c) || c == '=' || c == '>' || c == '/' })
+	// An attribute name cannot be empty, e.g. for a stray =.
+	if i == start {
+		i++
+	}
+	attr := htmlAttr{name: strings.ToLower(s[start:i])}
+	i = scanTo(s, i, isNotTagSpace)
+	if i >= len(s) || s[i] != '=' {
+		return attr, i
+	}
+	value, i := attrValue(s, scanTo(s, i+1, isNotTagSpace))
+	attr.value = html.UnescapeString(value)
+	return attr, i
+}
+
+// attrValue returns the quoted or unquoted attribute value at s[i] and the
+// index following it, or -1 if a quoted value is not terminated.
+func attrValue(s string, i int) (string, int) {
+	if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+		end := strings.IndexByte(s[i+1:], s[i])
+		if end < 0 {
+			return "", -1
+		}
+		return s[i+1 : i+1+end], i + end + 2
+	}
+	end := scanTo(s, i, func(c byte) bool { return isTagSpace(c) || c == '>' })
+	return s[i:end], end
+}
+
+// scanTo returns the index of the first byte of s from i for which stop is
+// true, or len(s).
+func scanTo(s string, i int, stop func(c byte) bool) int {
+	for i < len(s) && !stop(s[i]) {
+		i++
+	}
+	return i
+}
+
+func isASCIILetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isTagSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isNotTagSpace(c byte) bool {
+	return !isTagSpace(c)
+}
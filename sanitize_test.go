@@ -0,0 +1,84 @@
+package podcasts
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	cases := map[string]string{
+		"Allowed":      `<p>Hello <b>bold</b> <strong>strong</strong> <i>i</i> <em>em</em><br/>line</p>`,
+		"Attributes":   `<P CLASS="x" style="color:red">Hi</P>`,
+		"Link":         `<a href='https://example.com/?a=1&amp;b=2' target=_blank onclick="x()">link</a>`,
+		"Mailto":       `<a href="mailto:show@example.com">mail</a>`,
+		"UnsafeLink":   `<a href="javascript:alert(1)">click</a> <a href="/relative">relative</a> <a>none</a>`,
+		"Dropped":      `before<script>alert("</p>")</script><style>p{}</style><iframe src="x"></iframe>after`,
+		"Stripped":     `<span>a</span><font color="red">b</font><img src="x.png" alt="c"><u>d</u>`,
+		"Headings":     `<div><h2>Title</h2><p>Text</p></div>`,
+		"Lists":        `<ul><li>one<li>two</ul><ol><li>first</li></ol>`,
+		"ListInPara":   `<p>Items<ul><li>one</li></ul>after</p>`,
+		"Unbalanced":   `<b><i>bold italic</b> text</i></p>`,
+		"Comments":     `a<!-- <b>hidden</b> -->b<!DOCTYPE html><?xml version="1.0"?>c`,
+		"Text":         `1 < 2 && 3 > 2 &copy; &lt;b&gt; <3`,
+		"Unterminated": `text <b class="x`,
+		"OpenComment":  `a<!-- <b>b</b>`,
+		"OpenDoctype":  `<i>a</i><!DOCTYPE html`,
+		"OpenTag":      `<b>a</b> <i class="x>b</i> <b>c</b>`,
+		"Empty":        `<p></p><p><b></b></p>text`,
+	}
+	want := map[string]string{
+		"Allowed":      `<p>Hello <b>bold</b> <strong>strong</strong> <i>i</i> <em>em</em><br>line</p>`,
+		"Attributes":   `<p>Hi</p>`,
+		"Link":         `<a href="https://example.com/?a=1&amp;b=2">link</a>`,
+		"Mailto":       `<a href="mailto:show@example.com">mail</a>`,
+		"UnsafeLink":   `click relative none`,
+		"Dropped":      `beforeafter`,
+		"Stripped":     `abd`,
+		"Headings":     `<p>Title</p><p>Text</p>`,
+		"Lists":        `<ul><li>one</li><li>two</li></ul><ol><li>first</li></ol>`,
+		"ListInPara":   `<p>Items</p><ul><li>one</li></ul>after`,
+		"Unbalanced":   `<b><i>bold italic</i></b> text`,
+		"Comments":     `abc`,
+		"Text":         `1 &lt; 2 &amp;&amp; 3 &gt; 2 © &lt;b&gt; &lt;3`,
+		"Unterminated": `text &lt;b class="x`,
+		"OpenComment":  `a&lt;!-- <b>b</b>`,
+		"OpenDoctype":  `<i>a</i>&lt;!DOCTYPE html`,
+		"OpenTag":      `<b>a</b> &lt;i class="x&gt;b <b>c</b>`,
+		"Empty":        `text`,
+	}
+	for name, html := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := SanitizeHTML(html); got != want[name] {
+				t.Errorf("expected %v got %v", want[name], got)
+			}
+		})
+	}
+}
+
+func TestHTMLText(t *testing.T) {
+	html := `<p>Welcome to <b>episode   42</b>.<br>Second line</p>
+<ul><li>First</li><li><a href="https://example.com">Example</a></li></ul>
+<ol><li>One</li><li><a href="https://example.com">https://example.com</a></li></ol>
+<script>ignored()</script><p>Mail <a href="mailto:a@example.com">a@example.com</a></p>`
+	want := "Welcome to episode 42.\nSecond line\n\n- First\n- Example (https://example.com)\n\n1. One\n2. https://example.com\n\nMail a@example.com"
+	if got := HTMLText(html); got != want {
+		t.Errorf("expected %q got %q", want, got)
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	if got := truncateText("short", 10); got != "short" {
+		t.Errorf("expected %v got %v", "short", got)
+	}
+	if got := truncateText("the quick brown fox", 12); got != "the quick…" {
+		t.Errorf("expected %v got %v", "the quick…", got)
+	}
+	if got := truncateText(strings.Repeat("é", 20), 10); got != strings.Repeat("é", 9)+"…" {
+		t.Errorf("expected %v got %v", strings.Repeat("é", 9)+"…", got)
+	}
+	long := strings.Repeat("word ", 1000)
+	if got := truncateText(long, maxDescriptionLength); utf8.RuneCountInString(got) > maxDescriptionLength {
+		t.Errorf("expected at most %d characters got %d", maxDescriptionLength, utf8.RuneCountInString(got))
+	}
+}
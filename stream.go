@@ -0,0 +1,114 @@
+package podcasts
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// ItemIterator returns the next item of a streamed feed, and io.EOF once
+// every item was returned.
+type ItemIterator func() (*Item, error)
+
+// SliceItems returns an ItemIterator over given items.
+func SliceItems(items []*Item) ItemIterator {
+	return func() (*Item, error) {
+		if len(items) == 0 {
+			return nil, io.EOF
+		}
+		item := items[0]
+		items = items[1:]
+		return item, nil
+	}
+}
+
+// rawChannel is Channel without its XML methods.
+type rawChannel Channel
+
+// streamChannel represents a channel whose items are streamed. Items,
+// Categories and Extensions shadow the fields of rawChannel, so that they are
+// written in the same order as by Channel.
+type streamChannel struct {
+	XMLName xml.Name `xml:"channel"`
+	rawChannel
+	Items      itemStream        `xml:"item"`
+	Categories []*ItunesCategory `xml:"itunes:category"`
+	Extensions []*Extension      `xml:",any"`
+}
+
+// itemStream writes the items of the channel followed by the items returned by next.
+type itemStream struct {
+	items []*Item
+	next  ItemIterator
+}
+
+// MarshalXML marshalls each item as it is returned by the iterator.
+func (s itemStream) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	for _, item := range s.items {
+		if err := encodeStreamItem(encoder, start, item); err != nil {
+			return err
+		}
+	}
+	if s.next == nil {
+		return nil
+	}
+	for {
+		item, err := s.next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := encodeStreamItem(encoder, start, item); err != nil {
+			return err
+		}
+	}
+}
+
+func encodeStreamItem(encoder *xml.Encoder, start xml.StartElement, item *Item) error {
+	if item == nil {
+		return nil
+	}
+	return encoder.EncodeElement(item, start)
+}
+
+// WriteStream writes the feed to the given writer like Write, followed by the
+// items returned by next. Each item is encoded and written as soon as it is
+// returned, so that feeds with very large catalogs, read row by row from a
+// database for instance, are written with bounded memory.
+//
+// The items of the channel, if any, are written before the streamed ones.
+// Since the rss element is written before the streamed items are known, the
+// namespaces of item elements, such as podcast and psc, are always declared.
+func (f *Feed) WriteStream(w io.Writer, next ItemIterator) error {
+	if _, err := w.Write([]byte(xml.Header)); err != nil {
+		return err
+	}
+	channel := f.Channel
+	if channel == nil {
+		channel = &Channel{}
+	}
+	extensions := extensionPrefixes(channel)
+	start := f.startElement(func(ns builtinNamespace) bool {
+		return ns.items || ns.used(channel) || extensions[ns.prefix]
+	})
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	err := enc.Encode(streamChannel{
+		rawChannel: rawChannel(*channel),
+		Items:      itemStream{items: channel.Items, next: next},
+		Categories: channel.Categories,
+		Extensions: channel.Extensions,
+	})
+	if err != nil {
+		return err
+	}
+	if err := enc.EncodeToken(xml.EndElement{Name: start.Name}); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
@@ -0,0 +1,165 @@
+package podcasts
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupStreamPodcast(n int) *Podcast {
+	p := &Podcast{Title: "Stream", Link: "https://example.com", Description: "Streamed"}
+	for i := 1; i <= n; i++ {
+		p.AddItem(streamItem(i))
+	}
+	return p
+}
+
+func streamItem(i int) *Item {
+	return &Item{
+		Title:       fmt.Sprintf("Episode %d", i),
+		GUID:        fmt.Sprintf("https://example.com/%d", i),
+		PubDate:     NewPubDate(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(i) * time.Hour)),
+		Description: &CDATAText{Value: "<p>Show notes</p>"},
+		Duration:    NewDuration(30 * time.Minute),
+		Enclosure:   &Enclosure{URL: fmt.Sprintf("https://example.com/%d.mp3", i), Length: "1000", Type: MediaTypeMP3},
+		Transcripts: []*PodcastTranscript{{URL: fmt.Sprintf("https://example.com/%d.vtt", i), Type: "text/vtt"}},
+		SimpleChapters: &SimpleChapters{Version: pscVersion, Chapters: []*Chapter{
+			{Title: "Introduction"},
+			{Start: 5 * time.Minute, Title: "Interview"},
+		}},
+	}
+}
+
+func TestWriteStream(t *testing.T) {
+	p := setupStreamPodcast(3)
+	feed, err := p.Feed(Author(testAuthor), SelfLink("https://example.com/feed.xml"), func(f *Feed) error {
+		f.Channel.Categories = []*ItunesCategory{{Text: "Technology"}}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want, err := feed.XML()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	items := feed.Channel.Items
+	feed.Channel.Items = items[:1]
+	var buf bytes.Buffer
+	if err := feed.WriteStream(&buf, SliceItems(items[1:])); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("expected %v got %v", want, got)
+	}
+}
+
+func TestWriteStreamEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&Feed{Version: rssVersion}).WriteStream(&buf, nil); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	feed, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(feed.Channel.Items) != 0 {
+		t.Errorf("expected no items got %d", len(feed.Channel.Items))
+	}
+}
+
+func TestWriteStreamErrors(t *testing.T) {
+	feed, err := setupStreamPodcast(0).Feed()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	failure := errors.New("query failed")
+	count := 0
+	next := func() (*Item, error) {
+		count++
+		if count > 2 {
+			return nil, failure
+		}
+		return streamItem(count), nil
+	}
+	if err := feed.WriteStream(io.Discard, next); err != failure {
+		t.Errorf("expected %v got %v", failure, err)
+	}
+	if err := feed.WriteStream(&failingWriter{}, nil); err != ErrInvalidImage {
+		t.Errorf("expected %v got %v", ErrInvalidImage, err)
+	}
+}
+
+func TestSliceItems(t *testing.T) {
+	next := SliceItems([]*Item{{Title: "a"}, {Title: "b"}})
+	var got []string
+	for {
+		item, err := next()
+		if err == io.EOF {
+			break
+		}
+		got = append(got, item.Title)
+	}
+	if strings.Join(got, ",") != "a,b" {
+		t.Errorf("expected a,b got %v", got)
+	}
+}
+
+const benchmarkItems = 10000
+
+func BenchmarkWrite(b *testing.B) {
+	feed, err := setupStreamPodcast(benchmarkItems).Feed()
+	if err != nil {
+		b.Fatalf("unexpected error %v", err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := feed.Write(io.Discard); err != nil {
+			b.Fatalf("unexpected error %v", err)
+		}
+	}
+}
+
+func BenchmarkWriteStream(b *testing.B) {
+	p := setupStreamPodcast(benchmarkItems)
+	feed, err := (&Podcast{Title: p.Title, Link: p.Link, Description: p.Description}).Feed()
+	if err != nil {
+		b.Fatalf("unexpected error %v", err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := feed.WriteStream(io.Discard, SliceItems(p.items)); err != nil {
+			b.Fatalf("unexpected error %v", err)
+		}
+	}
+}
+
+func BenchmarkWriteStreamGenerated(b *testing.B) {
+	feed, err := setupStreamPodcast(0).Feed()
+	if err != nil {
+		b.Fatalf("unexpected error %v", err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Items are created as they are written, as if read from a database.
+		n := 0
+		next := func() (*Item, error) {
+			if n == benchmarkItems {
+				return nil, io.EOF
+			}
+			n++
+			return streamItem(n), nil
+		}
+		if err := feed.WriteStream(io.Discard, next); err != nil {
+			b.Fatalf("unexpected error %v", err)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package podcasts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxMP4TagSize is the largest MP4 metadata item read, so that cover art is skipped.
+const maxMP4TagSize = 64 * 1024
+
+// mediaTags represents the metadata tags read from a media file.
+type mediaTags struct {
+	Title   string
+	Artist  string
+	Comment string
+	Date    string
+	Track   int
+}
+
+// id3v22Frames maps the three character frame ids of ID3v2.2 to their ID3v2.3 equivalent.
+var id3v22Frames = map[string]string{
+	"TT2": "TIT2",
+	"TP1": "TPE1",
+	"COM": "COMM",
+	"TYE": "TYER",
+	"TDA": "TDAT",
+	"TRK": "TRCK",
+}
+
+// readTags reads the ID3v2 or MP4 tags of the media of given size. Media
+// without tags, or of a format without supported tags, has empty tags.
+func readTags(r io.ReaderAt, size int64) (*mediaTags, error) {
+	if size < 12 {
+		return &mediaTags{}, nil
+	}
+	head, err := readAt(r, 0, 12)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case string(head[:3]) == "ID3":
+		return readID3v2(r, size)
+	case string(head[4:8]) == "ftyp":
+		return readMP4Tags(r, size)
+	default:
+		return &mediaTags{}, nil
+	}
+}
+
+// readID3v2 reads the ID3v2.2, ID3v2.3 or ID3v2.4 tag at the start of the media.
+func readID3v2(r io.ReaderAt, size int64) (*mediaTags, error) {
+	header, err := readAt(r, 0, 10)
+	if err != nil {
+		return nil, err
+	}
+	major, flags := header[3], header[5]
+	tagSize := syncsafe(header[6:10])
+	if tagSize > size-10 {
+		tagSize = size - 10
+	}
+	data, err := readAt(r, 10, int(tagSize))
+	if err != nil {
+		return nil, err
+	}
+	if flags&0x80 != 0 && major < 4 {
+		data = removeUnsync(data)
+	}
+	if flags&0x40 != 0 && major > 2 && len(data) >= 4 {
+		extended := int(syncsafe(data[:4]))
+		if major == 3 {
+			extended = int(binary.BigEndian.Uint32(data)) + 4
+		}
+		if extended > len(data) {
+			return &mediaTags{}, nil
+		}
+		data = data[extended:]
+	}
+	return id3v2Tags(id3v2Frames(major, data)), nil
+}
+
+// id3v2Frames returns the body of the first frame with each id of the tag
+// data, with the ids of ID3v2.2 mapped to their ID3v2.3 equivalent.
+func id3v2Frames(major byte, data []byte) map[string][]byte {
+	headerSize := 10
+	if major == 2 {
+		headerSize = 6
+	}
+	frames := make(map[string][]byte)
+	for len(data) >= headerSize && data[0] != 0 {
+		id, frameSize, frameFlags := id3v2FrameHeader(major, data)
+		if frameSize <= 0 || frameSize > len(data)-headerSize {
+			break
+		}
+		body, ok := id3v2FrameBody(major, frameFlags, data[headerSize:headerSize+frameSize])
+		data = data[headerSize+frameSize:]
+		if !ok || id == "" {
+			continue
+		}
+		if _, seen := frames[id]; !seen {
+			frames[id] = body
+		}
+	}
+	return frames
+}
+
+// id3v2FrameHeader returns the id, size and flags of the frame at the start of data.
+func id3v2FrameHeader(major byte, data []byte) (string, int, byte) {
+	switch major {
+	case 2:
+		return id3v22Frames[string(data[:3])], int(data[3])<<16 | int(data[4])<<8 | int(data[5]), 0
+	case 3:
+		return string(data[:4]), int(binary.BigEndian.Uint32(data[4:8])), data[9]
+	default:
+		return string(data[:4]), int(syncsafe(data[4:8])), data[9]
+	}
+}
+
+// id3v2Tags maps the frames of an ID3v2 tag to media tags.
+func id3v2Tags(frames map[string][]byte) *mediaTags {
+	tags := &mediaTags{
+		Title:   id3v2Text(frames["TIT2"]),
+		Artist:  id3v2Text(frames["TPE1"]),
+		Comment: id3v2Comment(frames["COMM"]),
+		Date:    id3v2Text(frames["TDRC"]),
+		Track:   trackNumber(id3v2Text(frames["TRCK"])),
+	}
+	if tags.Date == "" {
+		tags.Date = id3v2Text(frames["TDRL"])
+	}
+	if tags.Date == "" {
+		tags.Date = id3v23Date(id3v2Text(frames["TYER"]), id3v2Text(frames["TDAT"]))
+	}
+	return tags
+}
+
+// id3v2FrameBody returns the content of a frame, undoing the encoding given by
+// the frame flags, or false for compressed and encrypted frames.
+func id3v2FrameBody(major, flags byte, body []byte) ([]byte, bool) {
+	switch major {
+	case 3:
+		if flags&0xC0 != 0 {
+			return nil, false
+		}
+		if flags&0x20 != 0 && len(body) > 0 {
+			// Group identifier.
+			body = body[1:]
+		}
+	case 4:
+		if flags&0x0C != 0 {
+			return nil, false
+		}
+		if flags&0x40 != 0 && len(body) > 0 {
+			// Group identifier.
+			body = body[1:]
+		}
+		if flags&0x01 != 0 && len(body) >= 4 {
+			// Data length indicator.
+			body = body[4:]
+		}
+		if flags&0x02 != 0 {
+			body = removeUnsync(body)
+		}
+	}
+	return body, true
+}
+
+// removeUnsync undoes the ID3v2 unsynchronisation scheme, which inserts a zero byte after each 0xFF.
+func removeUnsync(b []byte) []byte {
+	return bytes.ReplaceAll(b, []byte{0xFF, 0x00}, []byte{0xFF})
+}
+
+// id3v2Text decodes the first value of a text frame.
+func id3v2Text(body []byte) string {
+	if len(body) < 1 {
+		return ""
+	}
+	value, _ := splitTerminated(body[0], body[1:])
+	return strings.TrimSpace(decodeID3v2Text(body[0], value))
+}
+
+// id3v2Comment decodes the text of a comment frame, skipping its language and description.
+func id3v2Comment(body []byte) string {
+	if len(body) < 4 {
+		return ""
+	}
+	_, text := splitTerminated(body[0], body[4:])
+	text, _ = splitTerminated(body[0], text)
+	return strings.TrimSpace(decodeID3v2Text(body[0], text))
+}
+
+// splitTerminated splits b after the first string terminator of given encoding.
+func splitTerminated(encoding byte, b []byte) (value, rest []byte) {
+	if encoding == 1 || encoding == 2 {
+		for i := 0; i+1 < len(b); i += 2 {
+			if b[i] == 0 && b[i+1] == 0 {
+				return b[:i], b[i+2:]
+			}
+		}
+		return b, nil
+	}
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		return b[:i], b[i+1:]
+	}
+	return b, nil
+}
+
+// decodeID3v2Text decodes text in one of the ID3v2 encodings: ISO-8859-1,
+// UTF-16 with byte order mark, UTF-16BE or UTF-8.
+func decodeID3v2Text(encoding byte, b []byte) string {
+	switch encoding {
+	case 1, 2:
+		order := binary.ByteOrder(binary.BigEndian)
+		if len(b) >= 2 && b[0] == 0xFF && b[1] == 0xFE {
+			order, b = binary.LittleEndian, b[2:]
+		} else if len(b) >= 2 && b[0] == 0xFE && b[1] == 0xFF {
+			b = b[2:]
+		}
+		units := make([]uint16, len(b)/2)
+		for i := range units {
+			units[i] = order.Uint16(b[2*i:])
+		}
+		return string(utf16.Decode(units))
+	case 3:
+		return strings.ToValidUTF8(string(b), "\uFFFD")
+	default:
+		runes := make([]rune, len(b))
+		for i, c := range b {
+			runes[i] = rune(c)
+		}
+		return string(runes)
+	}
+}
+
+// id3v23Date joins the year and DDMM frames of ID3v2.3 into an ISO 8601 date.
+func id3v23Date(year, ddmm string) string {
+	if len(year) != 4 || !isDigits(year) {
+		return ""
+	}
+	if len(ddmm) != 4 || !isDigits(ddmm) {
+		return year
+	}
+	return year + "-" + ddmm[2:] + "-" + ddmm[:2]
+}
+
+// trackNumber parses a track number such as 3 or 3/12.
+func trackNumber(value string) int {
+	if i := strings.IndexByte(value, '/'); i >= 0 {
+		value = value[:i]
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// readMP4Tags reads the iTunes metadata items of the MPEG-4 file of given size.
+func readMP4Tags(r io.ReaderAt, size int64) (*mediaTags, error) {
+	tags := &mediaTags{}
+	var ilst func(boxType string, body, bodyEnd int64) error
+	ilst = func(boxType string, body, bodyEnd int64) error {
+		switch boxType {
+		case "moov", "udta":
+			return walkMP4(r, body, bodyEnd, ilst)
+		case "meta":
+			// meta is a full box in MPEG-4 files, and a plain box in QuickTime files.
+			version, err := readAt(r, body, 4)
+			if err != nil {
+				return err
+			}
+			if binary.BigEndian.Uint32(version) == 0 {
+				body += 4
+			}
+			return walkMP4(r, body, bodyEnd, ilst)
+		case "ilst":
+			return walkMP4(r, body, bodyEnd, func(itemType string, body, bodyEnd int64) error {
+				return readMP4Item(r, tags, itemType, body, bodyEnd)
+			})
+		}
+		return nil
+	}
+	if err := walkMP4(r, 0, size, ilst); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// readMP4Item reads the value of a metadata item into tags.
+func readMP4Item(r io.ReaderAt, tags *mediaTags, itemType string, body, bodyEnd int64) error {
+	var field *string
+	switch itemType {
+	case "\xa9nam":
+		field = &tags.Title
+	case "\xa9ART":
+		field = &tags.Artist
+	case "\xa9cmt":
+		field = &tags.Comment
+	case "\xa9day":
+		field = &tags.Date
+	case "trkn":
+		// The track number is binary, and read below.
+	default:
+		return nil
+	}
+	return walkMP4(r, body, bodyEnd, func(boxType string, body, bodyEnd int64) error {
+		// The data box starts with a type indicator and a locale.
+		if boxType != "data" || bodyEnd-body < 8 || bodyEnd-body > maxMP4TagSize {
+			return nil
+		}
+		value, err := readAt(r, body+8, int(bodyEnd-body-8))
+		if err != nil {
+			return err
+		}
+		if field == nil {
+			if len(value) >= 4 {
+				tags.Track = int(binary.BigEndian.Uint16(value[2:4]))
+			}
+		} else if *field == "" {
+			*field = strings.TrimSpace(string(value))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,124 @@
+package podcasts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func id3v2Frame(major byte, id string, body []byte) []byte {
+	size := len(body)
+	var header []byte
+	switch major {
+	case 2:
+		header = append([]byte(id), byte(size>>16), byte(size>>8), byte(size))
+	case 3:
+		header = append([]byte(id), 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(header[4:], uint32(size))
+	default:
+		header = append([]byte(id), byte(size>>21&0x7F), byte(size>>14&0x7F), byte(size>>7&0x7F), byte(size&0x7F), 0, 0)
+	}
+	return append(header, body...)
+}
+
+func id3v2(major byte, frames ...[]byte) []byte {
+	body := bytes.Join(frames, nil)
+	body = append(body, make([]byte, 16)...) // padding
+	size := len(body)
+	header := []byte{'I', 'D', '3', major, 0, 0, byte(size >> 21 & 0x7F), byte(size >> 14 & 0x7F), byte(size >> 7 & 0x7F), byte(size & 0x7F)}
+	return append(header, body...)
+}
+
+func latin1(s string) []byte {
+	return append([]byte{0}, s...)
+}
+
+func utf16LE(s string) []byte {
+	b := []byte{1, 0xFF, 0xFE}
+	for _, r := range s {
+		b = append(b, byte(r), byte(r>>8))
+	}
+	return b
+}
+
+func mp4Item(itemType string, value []byte) []byte {
+	return mp4Box(itemType, mp4Box("data", []byte{0, 0, 0, 1, 0, 0, 0, 0}, value))
+}
+
+func TestReadTags(t *testing.T) {
+	cases := map[string]struct {
+		data []byte
+		want mediaTags
+	}{
+		"ID3v2.2": {
+			id3v2(2,
+				id3v2Frame(2, "TT2", latin1("Old title")),
+				id3v2Frame(2, "TP1", latin1("Old artist")),
+				id3v2Frame(2, "TYE", latin1("1999")),
+				id3v2Frame(2, "TRK", latin1("2")),
+			),
+			mediaTags{Title: "Old title", Artist: "Old artist", Date: "1999", Track: 2},
+		},
+		"ID3v2.3": {
+			id3v2(3,
+				id3v2Frame(3, "TIT2", utf16LE("Épisode un")),
+				id3v2Frame(3, "TPE1", latin1("Caf\xe9 Radio")),
+				id3v2Frame(3, "COMM", append([]byte{1, 'e', 'n', 'g', 0xFF, 0xFE, 0, 0}, utf16LE("A comment")[1:]...)),
+				id3v2Frame(3, "TYER", latin1("2024")),
+				id3v2Frame(3, "TDAT", latin1("0502")),
+				id3v2Frame(3, "TRCK", latin1("7/12")),
+			),
+			mediaTags{Title: "Épisode un", Artist: "Café Radio", Comment: "A comment", Date: "2024-02-05", Track: 7},
+		},
+		"ID3v2.4": {
+			id3v2(4,
+				id3v2Frame(4, "TIT2", append([]byte{3}, "Title\x00Alternative"...)),
+				id3v2Frame(4, "TIT2", latin1("Duplicate")),
+				id3v2Frame(4, "COMM", append([]byte{3, 'e', 'n', 'g'}, "description\x00Comment"...)),
+				id3v2Frame(4, "TDRC", latin1("2024-03-01T10:30")),
+				id3v2Frame(4, "TRCK", latin1("x")),
+			),
+			mediaTags{Title: "Title", Comment: "Comment", Date: "2024-03-01T10:30"},
+		},
+		"MP4": {
+			bytes.Join([][]byte{
+				mp4Box("ftyp", []byte("M4A \x00\x00\x00\x00")),
+				mp4Box("moov",
+					mvhd(1000, 1000),
+					mp4Box("udta", mp4Box("meta", []byte{0, 0, 0, 0},
+						mp4Box("hdlr", make([]byte, 25)),
+						mp4Box("ilst",
+							mp4Item("\xa9nam", []byte("MP4 title")),
+							mp4Item("\xa9ART", []byte("MP4 artist")),
+							mp4Item("\xa9cmt", []byte("MP4 comment")),
+							mp4Item("\xa9day", []byte("2023-12-24")),
+							mp4Item("trkn", []byte{0, 0, 0, 9, 0, 10, 0, 0}),
+							mp4Item("covr", make([]byte, 100)),
+						),
+					)),
+				),
+			}, nil),
+			mediaTags{Title: "MP4 title", Artist: "MP4 artist", Comment: "MP4 comment", Date: "2023-12-24", Track: 9},
+		},
+		"Untagged": {mp3Frames(mpeg1Header, 417, 2), mediaTags{}},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			tags, err := readTags(bytes.NewReader(c.data), int64(len(c.data)))
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if *tags != c.want {
+				t.Errorf("expected %+v got %+v", c.want, *tags)
+			}
+		})
+	}
+}
+
+func TestRemoveUnsync(t *testing.T) {
+	got := removeUnsync([]byte{0xFF, 0x00, 0xE0, 0xFF, 0x00, 0x00})
+	want := []byte{0xFF, 0xE0, 0xFF, 0x00}
+	if !bytes.Equal(got, want) {
+		t.Errorf("expected %v got %v", want, got)
+	}
+}
@@ -0,0 +1,255 @@
+package podcasts
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidPubDate represents a error returned for unparseable publication date.
+	ErrInvalidPubDate = errors.New("podcasts: invalid pubDate")
+
+	// ErrInvalidDuration represents a error returned for unparseable duration.
+	ErrInvalidDuration = errors.New("podcasts: invalid duration")
+)
+
+// iso8601 lists the ISO 8601 layouts accepted in place of RFC 2822 dates.
+var iso8601 = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// zones maps the named time zones seen in feeds to their offsets in hours.
+var zones = map[string]float64{
+	"UT": 0, "UTC": 0, "GMT": 0, "Z": 0, "WET": 0,
+	"EST": -5, "EDT": -4, "CST": -6, "CDT": -5,
+	"MST": -7, "MDT": -6, "PST": -8, "PDT": -7,
+	"AKST": -9, "AKDT": -8, "HST": -10,
+	"BST": 1, "WEST": 1, "CET": 1, "CEST": 2, "EET": 2, "EEST": 3, "MSK": 3,
+	"JST": 9, "AWST": 8, "ACST": 9.5, "ACDT": 10.5, "AEST": 10, "AEDT": 11,
+	"NZST": 12, "NZDT": 13,
+}
+
+// months maps the month names and abbreviations seen in feeds to months.
+var months = map[string]time.Month{
+	"jan": time.January, "january": time.January,
+	"feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March,
+	"apr": time.April, "april": time.April,
+	"may": time.May,
+	"jun": time.June, "june": time.June,
+	"jul": time.July, "july": time.July,
+	"aug": time.August, "august": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September,
+	"oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November,
+	"dec": time.December, "december": time.December,
+}
+
+// ParsePubDate parses a publication date as found in real-world feeds.
+//
+// Besides the RFC 2822 format written by PubDate it accepts named time zones
+// (GMT, EST, ...), a missing time zone (assumed UTC), missing seconds, full
+// month names, two-digit years, an optional or mismatched day of the week and
+// ISO 8601 dates.
+func ParsePubDate(value string) (*PubDate, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, fmt.Errorf("%w: empty value", ErrInvalidPubDate)
+	}
+	for _, layout := range iso8601 {
+		if t, err := time.Parse(layout, value); err == nil {
+			return NewPubDate(t), nil
+		}
+	}
+	t, err := parseRFC2822(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrInvalidPubDate, value, err)
+	}
+	return NewPubDate(t), nil
+}
+
+// parseRFC2822 leniently parses a date in the [day,] dd mmm yy[yy] hh:mm[:ss] [zone] format.
+func parseRFC2822(value string) (time.Time, error) {
+	fields := strings.Fields(strings.ReplaceAll(value, ",", " "))
+	if len(fields) > 0 && isWeekday(fields[0]) {
+		fields = fields[1:]
+	}
+	if len(fields) < 4 || len(fields) > 5 {
+		return time.Time{}, errors.New("expected day, month, year, time and zone")
+	}
+	if _, ok := months[strings.ToLower(fields[0])]; ok {
+		fields[0], fields[1] = fields[1], fields[0]
+	}
+
+	day, err := strconv.Atoi(fields[0])
+	if err != nil || day < 1 || day > 31 {
+		return time.Time{}, fmt.Errorf("invalid day %q", fields[0])
+	}
+	month, ok := months[strings.ToLower(fields[1])]
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid month %q", fields[1])
+	}
+	year, err := parseYear(fields[2])
+	if err != nil {
+		return time.Time{}, err
+	}
+	hour, minute, sec, err := parseClock(fields[3])
+	if err != nil {
+		return time.Time{}, err
+	}
+	loc := time.UTC
+	if len(fields) == 5 {
+		if loc, err = parseZone(fields[4]); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	t := time.Date(year, month, day, hour, minute, sec, 0, loc)
+	if t.Day() != day {
+		return time.Time{}, fmt.Errorf("day %d out of range for %v", day, month)
+	}
+	return t, nil
+}
+
+// isWeekday reports whether s is the name or abbreviation of a day of the week.
+func isWeekday(s string) bool {
+	s = strings.ToLower(s)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if s == name || s == name[:3] {
+			return true
+		}
+	}
+	return false
+}
+
+// parseYear parses four-digit years and two-digit years as specified by RFC 2822.
+func parseYear(s string) (int, error) {
+	year, err := strconv.Atoi(s)
+	if err != nil || year < 0 {
+		return 0, fmt.Errorf("invalid year %q", s)
+	}
+	switch len(s) {
+	case 2:
+		if year < 50 {
+			return 2000 + year, nil
+		}
+		return 1900 + year, nil
+	case 4:
+		return year, nil
+	default:
+		return 0, fmt.Errorf("invalid year %q", s)
+	}
+}
+
+// parseClock parses time of the day in hh:mm:ss or hh:mm formats.
+func parseClock(s string) (hour, minute, sec int, err error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, 0, 0, fmt.Errorf("invalid time %q", s)
+	}
+	limits := []int{23, 59, 60}
+	values := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || n > limits[i] {
+			return 0, 0, 0, fmt.Errorf("invalid time %q", s)
+		}
+		values[i] = n
+	}
+	return values[0], values[1], values[2], nil
+}
+
+// parseZone parses numeric (+hhmm, +hh:mm) and named time zones.
+func parseZone(s string) (*time.Location, error) {
+	if offset, ok := zones[strings.ToUpper(s)]; ok {
+		return time.FixedZone(strings.ToUpper(s), int(offset*3600)), nil
+	}
+	if len(s) < 5 || (s[0] != '+' && s[0] != '-') {
+		return nil, fmt.Errorf("unknown time zone %q", s)
+	}
+	digits := strings.Replace(s[1:], ":", "", 1)
+	hours, herr := strconv.Atoi(digits[:2])
+	minutes, merr := strconv.Atoi(digits[2:])
+	if len(digits) != 4 || herr != nil || merr != nil || hours > 23 || minutes > 59 {
+		return nil, fmt.Errorf("invalid time zone offset %q", s)
+	}
+	offset := hours*3600 + minutes*60
+	if s[0] == '-' {
+		offset = -offset
+	}
+	return time.FixedZone("", offset), nil
+}
+
+// ParseDuration parses a duration as found in real-world feeds.
+//
+// Accepted formats are raw seconds (S or S.mmm), MM:SS and H:MM:SS, where the
+// seconds may have a fractional part and the leading component may exceed 59.
+func ParseDuration(value string) (*Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, fmt.Errorf("%w: empty value", ErrInvalidDuration)
+	}
+	d, err := parseDuration(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrInvalidDuration, value, err)
+	}
+	return NewDuration(d), nil
+}
+
+// parseDuration parses duration in these formats: H:MM:SS, MM:SS, S, with optional fractional seconds.
+func parseDuration(value string) (time.Duration, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) > 3 {
+		return 0, errors.New("too many components")
+	}
+	names := []string{"seconds", "minutes", "hours"}
+
+	last := len(parts) - 1
+	seconds, err := strconv.ParseFloat(parts[last], 64)
+	if err != nil || !isDigits(strings.Replace(parts[last], ".", "", 1)) {
+		return 0, fmt.Errorf("invalid seconds %q", parts[last])
+	}
+	if last > 0 && seconds >= 60 {
+		return 0, fmt.Errorf("seconds %q out of range", parts[last])
+	}
+
+	var total float64
+	for i, part := range parts[:last] {
+		n, err := strconv.Atoi(part)
+		name := names[last-i]
+		if err != nil || !isDigits(part) {
+			return 0, fmt.Errorf("invalid %s %q", name, part)
+		}
+		if i > 0 && n >= 60 {
+			return 0, fmt.Errorf("%s %q out of range", name, part)
+		}
+		total = total*60 + float64(n)
+	}
+	total = total*60 + seconds
+	if total*float64(time.Second) > math.MaxInt64 {
+		return 0, errors.New("duration out of range")
+	}
+	return time.Duration(math.Round(total * float64(time.Second))), nil
+}
+
+// isDigits reports whether s is a non-empty string of ASCII digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,140 @@
+package podcasts
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParsePubDate(t *testing.T) {
+	est := time.FixedZone("EST", -5*60*60)
+	cases := map[string]time.Time{
+		"Thu, 01 Jan 2015 00:00:00 +0000":  time.Date(2015, time.January, 1, 0, 0, 0, 0, time.UTC),
+		"Thu, 01 Jan 2015 00:00:00 GMT":    time.Date(2015, time.January, 1, 0, 0, 0, 0, time.UTC),
+		"Thu, 01 Jan 2015 10:00:00 EST":    time.Date(2015, time.January, 1, 10, 0, 0, 0, est),
+		"Thu, 01 Jan 2015 10:00:00 pdt":    time.Date(2015, time.January, 1, 17, 0, 0, 0, time.UTC),
+		"Thu, 01 Jan 2015 10:00 +0100":     time.Date(2015, time.January, 1, 9, 0, 0, 0, time.UTC),
+		"Thu, 01 Jan 2015 10:00:00 +05:30": time.Date(2015, time.January, 1, 4, 30, 0, 0, time.UTC),
+		"Thu, 01 Jan 15 00:00:00 +0000":    time.Date(2015, time.January, 1, 0, 0, 0, 0, time.UTC),
+		"Fri, 31 Dec 99 23:59:59 +0000":    time.Date(1999, time.December, 31, 23, 59, 59, 0, time.UTC),
+		"01 Jan 2015 00:00:00 +0000":       time.Date(2015, time.January, 1, 0, 0, 0, 0, time.UTC),
+		"Monday, 1 January 2015 9:05 UT":   time.Date(2015, time.January, 1, 9, 5, 0, 0, time.UTC),
+		"Jan 1 2015 09:05:00":              time.Date(2015, time.January, 1, 9, 5, 0, 0, time.UTC),
+		"  Thu,  01 Sept 2015 00:00:00 Z ": time.Date(2015, time.September, 1, 0, 0, 0, 0, time.UTC),
+		"2015-01-01T10:00:00+02:00":        time.Date(2015, time.January, 1, 8, 0, 0, 0, time.UTC),
+		"2015-01-01":                       time.Date(2015, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for value, want := range cases {
+		t.Run(value, func(t *testing.T) {
+			got, err := ParsePubDate(value)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !got.Equal(want) {
+				t.Errorf("expected %v got %v", want, got.Time)
+			}
+		})
+	}
+}
+
+func TestParsePubDateKeepsZoneName(t *testing.T) {
+	got, err := ParsePubDate("Thu, 01 Jan 2015 10:00:00 EST")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if name, offset := got.Zone(); name != "EST" || offset != -5*60*60 {
+		t.Errorf("expected EST -18000 got %v %v", name, offset)
+	}
+}
+
+func TestParsePubDateInvalid(t *testing.T) {
+	cases := map[string]string{
+		"":                                 `podcasts: invalid pubDate: empty value`,
+		"yesterday":                        `podcasts: invalid pubDate "yesterday": expected day, month, year, time and zone`,
+		"Thu, 41 Jan 2015 00:00:00 +0000":  `podcasts: invalid pubDate "Thu, 41 Jan 2015 00:00:00 +0000": invalid day "41"`,
+		"Thu, 01 Foo 2015 00:00:00 +0000":  `podcasts: invalid pubDate "Thu, 01 Foo 2015 00:00:00 +0000": invalid month "Foo"`,
+		"Thu, 01 Jan 201 00:00:00 +0000":   `podcasts: invalid pubDate "Thu, 01 Jan 201 00:00:00 +0000": invalid year "201"`,
+		"Thu, 01 Jan 2015 24:00:00 +0000":  `podcasts: invalid pubDate "Thu, 01 Jan 2015 24:00:00 +0000": invalid time "24:00:00"`,
+		"Thu, 01 Jan 2015 00:00:00 XYZ":    `podcasts: invalid pubDate "Thu, 01 Jan 2015 00:00:00 XYZ": unknown time zone "XYZ"`,
+		"Thu, 01 Jan 2015 00:00:00 +2500":  `podcasts: invalid pubDate "Thu, 01 Jan 2015 00:00:00 +2500": invalid time zone offset "+2500"`,
+		"Mon, 30 Feb 2015 00:00:00 +0000":  `podcasts: invalid pubDate "Mon, 30 Feb 2015 00:00:00 +0000": day 30 out of range for February`,
+		"Thu, 01 Jan 2015 00:00:00 +0000x": `podcasts: invalid pubDate "Thu, 01 Jan 2015 00:00:00 +0000x": invalid time zone offset "+0000x"`,
+	}
+	for value, want := range cases {
+		t.Run(value, func(t *testing.T) {
+			_, err := ParsePubDate(value)
+			if !errors.Is(err, ErrInvalidPubDate) {
+				t.Fatalf("expected ErrInvalidPubDate got %v", err)
+			}
+			if err.Error() != want {
+				t.Errorf("expected %v got %v", want, err)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"0":            0,
+		"94":           time.Second * 94,
+		"3600":         time.Hour,
+		"94.5":         time.Second*94 + time.Millisecond*500,
+		"1:34":         time.Second * 94,
+		"01:34":        time.Second * 94,
+		"75:00":        time.Minute * 75,
+		"1:02:03":      time.Hour + time.Minute*2 + time.Second*3,
+		"1:02:03.250":  time.Hour + time.Minute*2 + time.Second*3 + time.Millisecond*250,
+		"25:30:45":     time.Hour*25 + time.Minute*30 + time.Second*45,
+		" 00:00:05 \n": time.Second * 5,
+	}
+	for value, want := range cases {
+		t.Run(value, func(t *testing.T) {
+			got, err := ParseDuration(value)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if got.Duration != want {
+				t.Errorf("expected %v got %v", want, got.Duration)
+			}
+		})
+	}
+}
+
+func TestParseDurationInvalid(t *testing.T) {
+	cases := map[string]string{
+		"":          `podcasts: invalid duration: empty value`,
+		"-5":        `podcasts: invalid duration "-5": invalid seconds "-5"`,
+		"1e3":       `podcasts: invalid duration "1e3": invalid seconds "1e3"`,
+		"NaN":       `podcasts: invalid duration "NaN": invalid seconds "NaN"`,
+		"1:60":      `podcasts: invalid duration "1:60": seconds "60" out of range`,
+		"1:60:00":   `podcasts: invalid duration "1:60:00": minutes "60" out of range`,
+		"x:00:00":   `podcasts: invalid duration "x:00:00": invalid hours "x"`,
+		"1:x:00":    `podcasts: invalid duration "1:x:00": invalid minutes "x"`,
+		"1:2:3:4":   `podcasts: invalid duration "1:2:3:4": too many components`,
+		"1:00:00 h": `podcasts: invalid duration "1:00:00 h": invalid seconds "00 h"`,
+	}
+	for value, want := range cases {
+		t.Run(value, func(t *testing.T) {
+			_, err := ParseDuration(value)
+			if !errors.Is(err, ErrInvalidDuration) {
+				t.Fatalf("expected ErrInvalidDuration got %v", err)
+			}
+			if err.Error() != want {
+				t.Errorf("expected %v got %v", want, err)
+			}
+		})
+	}
+}
+
+func TestDurationRoundTrip(t *testing.T) {
+	for _, seconds := range []int{0, 6, 64, 125, 3600, 37000} {
+		want := time.Duration(seconds) * time.Second
+		got, err := ParseDuration(formatDuration(want))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if got.Duration != want {
+			t.Errorf("expected %v got %v", want, got.Duration)
+		}
+	}
+}
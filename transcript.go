@@ -0,0 +1,405 @@
+package podcasts
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// TranscriptSRT is the media type of SubRip transcripts.
+	TranscriptSRT = "application/x-subrip"
+	// TranscriptVTT is the media type of WebVTT transcripts.
+	TranscriptVTT = "text/vtt"
+	// TranscriptJSON is the media type of Podcast Index JSON transcripts.
+	TranscriptJSON = "application/json"
+
+	// transcriptJSONVersion is the version of the Podcast Index JSON transcript format written.
+	transcriptJSONVersion = "1.0.0"
+)
+
+var (
+	// ErrInvalidTranscript represents a error returned for a transcript file that cannot be parsed.
+	ErrInvalidTranscript = errors.New("podcasts: invalid transcript")
+
+	// ErrUnknownTranscriptType represents a error returned for a transcript media type other than SRT, WebVTT or JSON.
+	ErrUnknownTranscriptType = errors.New("podcasts: unknown transcript type")
+)
+
+// transcriptExtensions maps the transcript media types to their file extensions.
+var transcriptExtensions = map[string]string{
+	TranscriptSRT:  ".srt",
+	TranscriptVTT:  ".vtt",
+	TranscriptJSON: ".json",
+}
+
+// Cue represents a timed line of given transcript.
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	// Speaker is the name of the person speaking, if known.
+	Speaker string
+	Text    string
+}
+
+// Cues represents a transcript as timed cues, which can be read and written
+// as SRT, WebVTT or Podcast Index JSON, so that transcripts can be converted
+// between these formats.
+//
+// SRT has no notion of speakers: they are written as a "Speaker: " prefix of
+// the text, and read back as part of it.
+type Cues []*Cue
+
+// ParseTranscript reads a transcript of given media type, TranscriptSRT,
+// TranscriptVTT or TranscriptJSON.
+func ParseTranscript(r io.Reader, mediaType string) (Cues, error) {
+	switch mediaType {
+	case TranscriptSRT:
+		return ParseSRT(r)
+	case TranscriptVTT:
+		return ParseVTT(r)
+	case TranscriptJSON:
+		return ParseTranscriptJSON(r)
+	default:
+		return nil, ErrUnknownTranscriptType
+	}
+}
+
+// Write writes the transcript in given media type, TranscriptSRT,
+// TranscriptVTT or TranscriptJSON.
+func (c Cues) Write(w io.Writer, mediaType string) error {
+	switch mediaType {
+	case TranscriptSRT:
+		return c.WriteSRT(w)
+	case TranscriptVTT:
+		return c.WriteVTT(w)
+	case TranscriptJSON:
+		return c.WriteJSON(w)
+	default:
+		return ErrUnknownTranscriptType
+	}
+}
+
+// ConvertTranscript converts the transcript read from r in media type from
+// into media type to, and writes it to w.
+func ConvertTranscript(w io.Writer, to string, r io.Reader, from string) error {
+	if _, ok := transcriptExtensions[to]; !ok {
+		return ErrUnknownTranscriptType
+	}
+	cues, err := ParseTranscript(r, from)
+	if err != nil {
+		return err
+	}
+	return cues.Write(w, to)
+}
+
+// ItemTranscripts adds a podcast:transcript of given item for each media
+// type, whose url is baseURL followed by the file extension of the type,
+// e.g. https://example.com/1/transcript.vtt. SRT and WebVTT transcripts are
+// timed, so they are marked as captions.
+func ItemTranscripts(baseURL, language string, mediaTypes ...string) func(i *Item) error {
+	return func(i *Item) error {
+		if err := checkAbsoluteURL(baseURL, ErrInvalidURL); err != nil {
+			return err
+		}
+		for _, mediaType := range mediaTypes {
+			ext, ok := transcriptExtensions[mediaType]
+			if !ok {
+				return ErrUnknownTranscriptType
+			}
+			transcript := &PodcastTranscript{URL: baseURL + ext, Type: mediaType, Language: language}
+			if mediaType != TranscriptJSON {
+				transcript.Rel = "captions"
+			}
+			i.Transcripts = append(i.Transcripts, transcript)
+		}
+		return nil
+	}
+}
+
+// ParseSRT reads a SubRip transcript.
+func ParseSRT(r io.Reader) (Cues, error) {
+	blocks, err := readBlocks(r)
+	if err != nil {
+		return nil, err
+	}
+	cues := make(Cues, 0, len(blocks))
+	for _, block := range blocks {
+		// The first line is the cue number, which is not kept.
+		if !strings.Contains(block[0], "-->") {
+			block = block[1:]
+		}
+		cue, err := parseCue(block)
+		if err != nil {
+			return nil, err
+		}
+		cues = append(cues, cue)
+	}
+	return cues, nil
+}
+
+// WriteSRT writes the transcript as SubRip.
+func (c Cues) WriteSRT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	n := 0
+	for _, cue := range c {
+		if cue == nil {
+			continue
+		}
+		n++
+		text := cueText(cue.Text)
+		if cue.Speaker != "" {
+			text = cue.Speaker + ": " + text
+		}
+		fmt.Fprintf(bw, "%d\n%s --> %s\n%s\n\n", n, formatCueTime(cue.Start, ','), formatCueTime(cue.End, ','), text)
+	}
+	return bw.Flush()
+}
+
+// ParseVTT reads a WebVTT transcript. Voice spans at the start of cues,
+// <v Speaker>, are read as the speaker; notes, styles and regions are ignored.
+// Character references, such as &amp;, are unescaped.
+func ParseVTT(r io.Reader) (Cues, error) {
+	blocks, err := readBlocks(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) == 0 || !isVTTSignature(blocks[0][0]) {
+		return nil, ErrInvalidTranscript
+	}
+	cues := make(Cues, 0, len(blocks)-1)
+	for _, block := range blocks[1:] {
+		if strings.HasPrefix(block[0], "NOTE") || block[0] == "STYLE" || block[0] == "REGION" {
+			continue
+		}
+		// A cue may start with an identifier line.
+		if !strings.Contains(block[0], "-->") {
+			block = block[1:]
+		}
+		cue, err := parseCue(block)
+		if err != nil {
+			return nil, err
+		}
+		cue.Speaker, cue.Text = parseVoice(cue.Text)
+		cue.Speaker, cue.Text = html.UnescapeString(cue.Speaker), html.UnescapeString(cue.Text)
+		cues = append(cues, cue)
+	}
+	return cues, nil
+}
+
+// WriteVTT writes the transcript as WebVTT, with speakers as voice spans.
+// Ampersands and angle brackets in the text and speakers are escaped.
+func (c Cues) WriteVTT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("WEBVTT\n\n")
+	for _, cue := range c {
+		if cue == nil {
+			continue
+		}
+		text := vttEscaper.Replace(cueText(cue.Text))
+		if cue.Speaker != "" {
+			text = "<v " + vttEscaper.Replace(cue.Speaker) + ">" + text
+		}
+		fmt.Fprintf(bw, "%s --> %s\n%s\n\n", formatCueTime(cue.Start, '.'), formatCueTime(cue.End, '.'), text)
+	}
+	return bw.Flush()
+}
+
+// transcriptJSON represents a Podcast Index JSON transcript file.
+type transcriptJSON struct {
+	Version  string         `json:"version"`
+	Segments []*segmentJSON `json:"segments"`
+}
+
+type segmentJSON struct {
+	Speaker   string  `json:"speaker,omitempty"`
+	StartTime float64 `json:"startTime"`
+	EndTime   float64 `json:"endTime"`
+	Body      string  `json:"body"`
+}
+
+// ParseTranscriptJSON reads a Podcast Index JSON transcript.
+func ParseTranscriptJSON(r io.Reader) (Cues, error) {
+	var file transcriptJSON
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTranscript, err)
+	}
+	cues := make(Cues, 0, len(file.Segments))
+	for _, segment := range file.Segments {
+		if segment == nil || segment.StartTime < 0 || segment.EndTime < segment.StartTime {
+			return nil, ErrInvalidTranscript
+		}
+		cues = append(cues, &Cue{
+			Start:   secondsDuration(segment.StartTime),
+			End:     secondsDuration(segment.EndTime),
+			Speaker: segment.Speaker,
+			Text:    segment.Body,
+		})
+	}
+	return cues, nil
+}
+
+// WriteJSON writes the transcript as Podcast Index JSON.
+func (c Cues) WriteJSON(w io.Writer) error {
+	file := &transcriptJSON{Version: transcriptJSONVersion, Segments: make([]*segmentJSON, 0, len(c))}
+	for _, cue := range c {
+		if cue == nil {
+			continue
+		}
+		file.Segments = append(file.Segments, &segmentJSON{
+			Speaker:   cue.Speaker,
+			StartTime: cue.Start.Seconds(),
+			EndTime:   cue.End.Seconds(),
+			Body:      cue.Text,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(file)
+}
+
+// readBlocks reads the blocks of lines separated by blank lines of a SRT or WebVTT file.
+func readBlocks(r io.Reader) ([][]string, error) {
+	var blocks [][]string
+	var block []string
+	scanner := bufio.NewScanner(r)
+	first := true
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if first {
+			line = strings.TrimPrefix(line, "\ufeff")
+			first = false
+		}
+		if strings.TrimSpace(line) == "" {
+			if len(block) > 0 {
+				blocks = append(blocks, block)
+				block = nil
+			}
+			continue
+		}
+		block = append(block, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(block) > 0 {
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// parseCue parses a timing line followed by the text of the cue.
+func parseCue(block []string) (*Cue, error) {
+	if len(block) == 0 {
+		return nil, ErrInvalidTranscript
+	}
+	i := strings.Index(block[0], "-->")
+	if i < 0 {
+		return nil, ErrInvalidTranscript
+	}
+	start, err := parseCueTime(strings.TrimSpace(block[0][:i]))
+	if err != nil {
+		return nil, err
+	}
+	// WebVTT cue settings may follow the end time.
+	fields := strings.Fields(block[0][i+len("-->"):])
+	if len(fields) == 0 {
+		return nil, ErrInvalidTranscript
+	}
+	end, err := parseCueTime(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	if end < start {
+		return nil, ErrInvalidTranscript
+	}
+	return &Cue{Start: start, End: end, Text: strings.Join(block[1:], "\n")}, nil
+}
+
+// parseCueTime parses a HH:MM:SS.mmm or MM:SS.mmm timestamp, with a comma or a dot before the milliseconds.
+func parseCueTime(value string) (time.Duration, error) {
+	i := strings.LastIndexAny(value, ",.")
+	if i < 0 || len(value)-i-1 != 3 {
+		return 0, ErrInvalidTranscript
+	}
+	ms, err := strconv.Atoi(value[i+1:])
+	if err != nil || ms < 0 {
+		return 0, ErrInvalidTranscript
+	}
+	parts := strings.Split(value[:i], ":")
+	if len(parts) != 2 && len(parts) != 3 {
+		return 0, ErrInvalidTranscript
+	}
+	var total int
+	for j, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || (j > 0 && n >= 60) || len(part) < 2 {
+			return 0, ErrInvalidTranscript
+		}
+		total = total*60 + n
+	}
+	return time.Duration(total)*time.Second + time.Duration(ms)*time.Millisecond, nil
+}
+
+// formatCueTime formats d as HH:MM:SS followed by sep and the milliseconds.
+func formatCueTime(d time.Duration, sep byte) string {
+	s := formatChapterTime(d)
+	return s[:len(s)-4] + string(sep) + s[len(s)-3:]
+}
+
+// cueText removes the blank lines of text, which would end the cue in SRT and WebVTT.
+func cueText(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// vttEscaper escapes the characters WebVTT does not allow in cue text, which
+// also keeps "-->" out of it.
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// isVTTSignature reports whether line is the WEBVTT signature, optionally
+// followed by a space or tab and a title.
+func isVTTSignature(line string) bool {
+	return line == "WEBVTT" || strings.HasPrefix(line, "WEBVTT ") || strings.HasPrefix(line, "WEBVTT\t")
+}
+
+// parseVoice returns the speaker of a WebVTT voice span at the start of text, and the text without it.
+func parseVoice(text string) (string, string) {
+	if !strings.HasPrefix(text, "<v") || len(text) < 3 || (text[2] != ' ' && text[2] != '.') {
+		return "", text
+	}
+	end := strings.Index(text, ">")
+	if end < 0 {
+		return "", text
+	}
+	annotation := text[2:end]
+	if annotation[0] == '.' {
+		// Classes, as in <v.loud Speaker>, come before the name.
+		space := strings.IndexByte(annotation, ' ')
+		if space < 0 {
+			return "", text
+		}
+		annotation = annotation[space:]
+	}
+	speaker := strings.TrimSpace(annotation)
+	rest := strings.Replace(text[end+1:], "</v>", "", 1)
+	return speaker, rest
+}
+
+// secondsDuration converts seconds to a duration rounded to the millisecond.
+func secondsDuration(seconds float64) time.Duration {
+	return time.Duration(seconds*1000+0.5) * time.Millisecond
+}
@@ -0,0 +1,249 @@
+package podcasts
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupCues() Cues {
+	return Cues{
+		{Start: 0, End: 2500 * time.Millisecond, Speaker: "Alice", Text: "Welcome to the show."},
+		{Start: 2500 * time.Millisecond, End: time.Hour + 5*time.Second, Speaker: "Bob", Text: "Thanks for having me,\n& hello."},
+		{Start: time.Hour + 5*time.Second, End: time.Hour + 6*time.Second, Text: "[music]"},
+	}
+}
+
+const (
+	testSRT = `1
+00:00:00,000 --> 00:00:02,500
+Alice: Welcome to the show.
+
+2
+00:00:02,500 --> 01:00:05,000
+Bob: Thanks for having me,
+& hello.
+
+3
+01:00:05,000 --> 01:00:06,000
+[music]
+
+`
+
+	testVTT = `WEBVTT
+
+00:00:00.000 --> 00:00:02.500
+<v Alice>Welcome to the show.
+
+00:00:02.500 --> 01:00:05.000
+<v Bob>Thanks for having me,
+&amp; hello.
+
+01:00:05.000 --> 01:00:06.000
+[music]
+
+`
+
+	testTranscriptJSON = `{
+  "version": "1.0.0",
+  "segments": [
+    {
+      "speaker": "Alice",
+      "startTime": 0,
+      "endTime": 2.5,
+      "body": "Welcome to the show."
+    },
+    {
+      "speaker": "Bob",
+      "startTime": 2.5,
+      "endTime": 3605,
+      "body": "Thanks for having me,\n& hello."
+    },
+    {
+      "startTime": 3605,
+      "endTime": 3606,
+      "body": "[music]"
+    }
+  ]
+}
+`
+)
+
+func TestWriteTranscript(t *testing.T) {
+	cases := map[string]string{
+		TranscriptSRT:  testSRT,
+		TranscriptVTT:  testVTT,
+		TranscriptJSON: testTranscriptJSON,
+	}
+	for mediaType, want := range cases {
+		t.Run(mediaType, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := setupCues().Write(&buf, mediaType); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if got := buf.String(); got != want {
+				t.Errorf("expected %v got %v", want, got)
+			}
+		})
+	}
+	if err := setupCues().Write(&bytes.Buffer{}, "text/plain"); err != ErrUnknownTranscriptType {
+		t.Errorf("expected %v got %v", ErrUnknownTranscriptType, err)
+	}
+}
+
+func TestParseTranscript(t *testing.T) {
+	cases := map[string]string{
+		TranscriptVTT:  testVTT,
+		TranscriptJSON: testTranscriptJSON,
+	}
+	for mediaType, data := range cases {
+		t.Run(mediaType, func(t *testing.T) {
+			cues, err := ParseTranscript(strings.NewReader(data), mediaType)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if want := setupCues(); !reflect.DeepEqual(cues, want) {
+				t.Errorf("expected %+v got %+v", want, cues)
+			}
+		})
+	}
+
+	// SRT has no speakers, which are read back as part of the text.
+	cues, err := ParseSRT(strings.NewReader(testSRT))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(cues) != 3 || cues[0].Speaker != "" || cues[0].Text != "Alice: Welcome to the show." || cues[1].End != time.Hour+5*time.Second {
+		t.Errorf("unexpected cues %+v", cues)
+	}
+}
+
+func TestParseVTT(t *testing.T) {
+	data := "\ufeffWEBVTT - Episode 1\r\nKind: captions\r\n\r\n" +
+		"NOTE written by hand\r\n\r\n" +
+		"STYLE\r\n::cue { color: white }\r\n\r\n" +
+		"intro\r\n00:01.000 --> 00:02.000 align:start line:0\r\n<v.loud Alice Smith>Hello</v>\r\n\r\n" +
+		"00:02.000 --> 00:03.000\r\n<i>Music</i>\r\n"
+	cues, err := ParseVTT(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := Cues{
+		{Start: time.Second, End: 2 * time.Second, Speaker: "Alice Smith", Text: "Hello"},
+		{Start: 2 * time.Second, End: 3 * time.Second, Text: "<i>Music</i>"},
+	}
+	if !reflect.DeepEqual(cues, want) {
+		t.Errorf("expected %+v got %+v", want, cues)
+	}
+}
+
+func TestParseTranscriptErrors(t *testing.T) {
+	cases := map[string]struct {
+		mediaType string
+		data      string
+	}{
+		"NoSignature":  {TranscriptVTT, "00:00.000 --> 00:01.000\nHello\n"},
+		"NoTiming":     {TranscriptSRT, "1\nHello\n"},
+		"NoEnd":        {TranscriptSRT, "1\n00:00:00,000 -->\nHello\n"},
+		"BadTime":      {TranscriptSRT, "1\n00:00:00 --> 00:00:01,000\nHello\n"},
+		"BadMinutes":   {TranscriptVTT, "WEBVTT\n\n00:60.000 --> 01:00.000\nHello\n"},
+		"EndBefore":    {TranscriptVTT, "WEBVTT\n\n00:02.000 --> 00:01.000\nHello\n"},
+		"JSONSyntax":   {TranscriptJSON, "{"},
+		"JSONNegative": {TranscriptJSON, `{"segments":[{"startTime":-1,"endTime":1,"body":"Hello"}]}`},
+		"JSONNil":      {TranscriptJSON, `{"segments":[null]}`},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseTranscript(strings.NewReader(c.data), c.mediaType); err == nil {
+				t.Errorf("expected error got %v", err)
+			}
+		})
+	}
+	if _, err := ParseTranscript(strings.NewReader(testSRT), "text/html"); err != ErrUnknownTranscriptType {
+		t.Errorf("expected %v got %v", ErrUnknownTranscriptType, err)
+	}
+}
+
+func TestConvertTranscript(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ConvertTranscript(&buf, TranscriptJSON, strings.NewReader(testVTT), TranscriptVTT); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := buf.String(); got != testTranscriptJSON {
+		t.Errorf("expected %v got %v", testTranscriptJSON, got)
+	}
+	buf.Reset()
+	if err := ConvertTranscript(&buf, TranscriptSRT, strings.NewReader(testTranscriptJSON), TranscriptJSON); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := buf.String(); got != testSRT {
+		t.Errorf("expected %v got %v", testSRT, got)
+	}
+	if err := ConvertTranscript(&buf, "text/plain", strings.NewReader(testVTT), TranscriptVTT); err != ErrUnknownTranscriptType {
+		t.Errorf("expected %v got %v", ErrUnknownTranscriptType, err)
+	}
+}
+
+func TestItemTranscripts(t *testing.T) {
+	item, err := NewItem("Episode 1", "https://example.com/1",
+		ItemTranscripts("https://example.com/1/transcript", "en", TranscriptVTT, TranscriptJSON),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := []*PodcastTranscript{
+		{URL: "https://example.com/1/transcript.vtt", Type: TranscriptVTT, Language: "en", Rel: "captions"},
+		{URL: "https://example.com/1/transcript.json", Type: TranscriptJSON, Language: "en"},
+	}
+	if !reflect.DeepEqual(item.Transcripts, want) {
+		t.Errorf("expected %+v got %+v", want, item.Transcripts)
+	}
+
+	if _, err := NewItem("Episode", "guid", ItemTranscripts("transcript", "en", TranscriptSRT)); err != ErrInvalidURL {
+		t.Errorf("expected %v got %v", ErrInvalidURL, err)
+	}
+	if _, err := NewItem("Episode", "guid", ItemTranscripts("https://example.com/transcript", "en", "text/html")); err != ErrUnknownTranscriptType {
+		t.Errorf("expected %v got %v", ErrUnknownTranscriptType, err)
+	}
+}
+
+func TestWriteTranscriptBlankLines(t *testing.T) {
+	var buf bytes.Buffer
+	cues := Cues{{End: time.Second, Text: "First paragraph.\n\nSecond paragraph."}, nil}
+	if err := cues.WriteVTT(&buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	parsed, err := ParseVTT(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(parsed) != 1 || parsed[0].Text != "First paragraph.\nSecond paragraph." {
+		t.Errorf("unexpected cues %+v", parsed)
+	}
+}
+
+func TestVTTEscaping(t *testing.T) {
+	cues := Cues{
+		{End: time.Second, Speaker: "Tom & <Jerry>", Text: "1 < 2 & 3 > 2"},
+		{Start: time.Second, End: 2 * time.Second, Text: "A --> B"},
+	}
+	var buf bytes.Buffer
+	if err := cues.WriteVTT(&buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := "WEBVTT\n\n" +
+		"00:00:00.000 --> 00:00:01.000\n<v Tom &amp; &lt;Jerry&gt;>1 &lt; 2 &amp; 3 &gt; 2\n\n" +
+		"00:00:01.000 --> 00:00:02.000\nA --&gt; B\n\n"
+	if got := buf.String(); got != want {
+		t.Errorf("expected %v got %v", want, got)
+	}
+	parsed, err := ParseVTT(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(parsed, cues) {
+		t.Errorf("expected %+v got %+v", cues, parsed)
+	}
+}
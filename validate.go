@@ -0,0 +1,466 @@
+package podcasts
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Severity represents how serious a validation problem is.
+type Severity int
+
+const (
+	// SeverityWarning marks a problem with a tag recommended by Apple Podcasts.
+	SeverityWarning Severity = iota
+	// SeverityError marks a problem with a tag required by Apple Podcasts.
+	SeverityError
+)
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return "Severity(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// ValidationError represents a single problem found in a feed.
+type ValidationError struct {
+	// Field is the path of the offending field, e.g. Channel.Items[1].Enclosure.Type.
+	Field    string
+	Severity Severity
+	Message  string
+}
+
+// Error returns the problem as a string.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", e.Severity, e.Field, e.Message)
+}
+
+// ValidationErrors represents every problem found in a feed.
+type ValidationErrors []*ValidationError
+
+// Error returns all problems as a string.
+func (v ValidationErrors) Error() string {
+	problems := make([]string, 0, len(v))
+	for _, problem := range v {
+		problems = append(problems, problem.Error())
+	}
+	return strings.Join(problems, "; ")
+}
+
+// Errors returns the problems with SeverityError.
+func (v ValidationErrors) Errors() ValidationErrors {
+	return v.filter(SeverityError)
+}
+
+// Warnings returns the problems with SeverityWarning.
+func (v ValidationErrors) Warnings() ValidationErrors {
+	return v.filter(SeverityWarning)
+}
+
+// Err returns the problems with SeverityError as an error, or nil if there are none.
+func (v ValidationErrors) Err() error {
+	if errs := v.Errors(); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (v ValidationErrors) filter(severity Severity) ValidationErrors {
+	var filtered ValidationErrors
+	for _, problem := range v {
+		if problem.Severity == severity {
+			filtered = append(filtered, problem)
+		}
+	}
+	return filtered
+}
+
+var (
+	// explicitValues lists the accepted values of itunes:explicit.
+	explicitValues = []string{"true", "false", ValueYes, "no", "clean"}
+
+	// languageCode matches ISO 639 language codes with optional subtags, e.g. en or en-US.
+	languageCode = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{1,8})*$`)
+)
+
+// Validate checks the feed against the tags required and recommended by
+// Apple Podcasts. It returns every problem found, or nil if there are none.
+func (f *Feed) Validate() ValidationErrors {
+	v := &validator{prefixes: f.prefixes()}
+	if f.Channel == nil {
+		v.errorf("Channel", "channel is required")
+		return v.problems
+	}
+	v.channel(f.Channel)
+	guids := make(map[string]int)
+	for i, item := range f.Channel.Items {
+		field := fmt.Sprintf("Channel.Items[%d]", i)
+		if item == nil {
+			v.errorf(field, "item is required")
+			continue
+		}
+		v.item(field, item)
+		if f.Channel.Type == Serial && item.Episode == 0 && (item.EpisodeType == "" || item.EpisodeType == EpisodeFull) {
+			v.errorf(field+".Episode", "episode number is required for serial shows")
+		}
+		if item.GUID == "" {
+			continue
+		}
+		if first, ok := guids[item.GUID]; ok {
+			v.errorf(field+".GUID", "duplicate guid %q, also used by Channel.Items[%d]", item.GUID, first)
+		} else {
+			guids[item.GUID] = i
+		}
+	}
+	return v.problems
+}
+
+// validator collects the problems found in a feed.
+type validator struct {
+	problems ValidationErrors
+	// prefixes are the namespace prefixes extensions may use.
+	prefixes map[string]bool
+}
+
+func (v *validator) errorf(field, format string, args ...interface{}) {
+	v.add(field, SeverityError, format, args...)
+}
+
+func (v *validator) warnf(field, format string, args ...interface{}) {
+	v.add(field, SeverityWarning, format, args...)
+}
+
+func (v *validator) add(field string, severity Severity, format string, args ...interface{}) {
+	v.problems = append(v.problems, &ValidationError{
+		Field:    field,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) channel(c *Channel) {
+	v.channelText(c)
+	v.artwork(c)
+	if c.Author == "" {
+		v.warnf("Channel.Author", "author is recommended")
+	}
+	v.rssElements(c)
+	v.showElements(c)
+	v.cdata("Channel.Summary", c.Summary)
+	v.extensions("Channel", c.Extensions, c.ExtensionAttrs)
+}
+
+func (v *validator) channelText(c *Channel) {
+	if c.Title == "" {
+		v.errorf("Channel.Title", "title is required")
+	}
+	if c.Description == "" {
+		v.errorf("Channel.Description", "description is required")
+	}
+	if c.Link == "" {
+		v.warnf("Channel.Link", "link is recommended")
+	} else {
+		v.absoluteURL("Channel.Link", c.Link)
+	}
+	if c.Language == "" {
+		v.errorf("Channel.Language", "language is required")
+	} else if !languageCode.MatchString(c.Language) {
+		v.warnf("Channel.Language", "language %q is not an ISO 639 code", c.Language)
+	}
+}
+
+func (v *validator) artwork(c *Channel) {
+	if c.Image == nil || c.Image.Href == "" {
+		v.errorf("Channel.Image", "artwork is required")
+	} else {
+		v.image("Channel.Image.Href", c.Image.Href)
+	}
+	if len(c.Categories) == 0 {
+		v.errorf("Channel.Categories", "at least one category is required")
+	}
+	for i, category := range c.Categories {
+		v.category(fmt.Sprintf("Channel.Categories[%d]", i), category)
+	}
+	if c.Explicit == "" {
+		v.errorf("Channel.Explicit", "explicit is required")
+	} else {
+		v.explicit("Channel.Explicit", c.Explicit)
+	}
+}
+
+func (v *validator) rssElements(c *Channel) {
+	v.atomLinks(c.AtomLinks)
+	if c.TTL < 0 {
+		v.errorf("Channel.TTL", "ttl %d must be positive", c.TTL)
+	}
+	v.skip(c)
+}
+
+func (v *validator) showElements(c *Channel) {
+	if c.Type != "" && c.Type != Episodic && c.Type != Serial {
+		v.errorf("Channel.Type", "type %q must be %s or %s", c.Type, Episodic, Serial)
+	}
+	v.owner(c.Owner)
+	if c.Locked != nil && c.Locked.Value != ValueYes && c.Locked.Value != "no" {
+		v.errorf("Channel.Locked.Value", "locked %q must be yes or no", c.Locked.Value)
+	}
+	for i, funding := range c.Funding {
+		field := fmt.Sprintf("Channel.Funding[%d]", i)
+		if funding == nil {
+			v.errorf(field, "funding is required")
+			continue
+		}
+		v.absoluteURL(field+".URL", funding.URL)
+	}
+}
+
+func (v *validator) atomLinks(links []*AtomLink) {
+	self := false
+	for i, link := range links {
+		field := fmt.Sprintf("Channel.AtomLinks[%d]", i)
+		if link == nil {
+			v.errorf(field, "atom:link is required")
+			continue
+		}
+		v.absoluteURL(field+".Href", link.Href)
+		self = self || link.Rel == "self"
+	}
+	if !self {
+		v.warnf("Channel.AtomLinks", "atom:link with rel=\"self\" is recommended")
+	}
+}
+
+func (v *validator) skip(c *Channel) {
+	if c.SkipHours != nil {
+		for i, hour := range c.SkipHours.Hours {
+			if hour < 0 || hour > 23 {
+				v.errorf(fmt.Sprintf("Channel.SkipHours.Hours[%d]", i), "hour %d must be from 0 to 23", hour)
+			}
+		}
+	}
+	if c.SkipDays != nil {
+		for i, day := range c.SkipDays.Days {
+			if !isDayName(day) {
+				v.errorf(fmt.Sprintf("Channel.SkipDays.Days[%d]", i), "day %q must be the name of a day of the week", day)
+			}
+		}
+	}
+}
+
+// isDayName reports whether s is the capitalised name of a day of the week, e.g. Monday.
+func isDayName(s string) bool {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if s == day.String() {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *validator) owner(owner *ItunesOwner) {
+	if owner == nil {
+		v.warnf("Channel.Owner", "owner is recommended")
+		return
+	}
+	if owner.Email == "" {
+		v.warnf("Channel.Owner.Email", "owner email is recommended")
+		return
+	}
+	if addr, err := mail.ParseAddress(owner.Email); err != nil || addr.Address != owner.Email {
+		v.errorf("Channel.Owner.Email", "invalid email %q", owner.Email)
+	}
+}
+
+func (v *validator) category(field string, category *ItunesCategory) {
+	if category == nil || category.Text == "" {
+		v.errorf(field+".Text", "category text is required")
+		return
+	}
+	main := CategoryName(category.Text)
+	if !IsCategory(main) {
+		v.errorf(field+".Text", "unknown category %q", category.Text)
+	}
+	for i, sub := range category.Categories {
+		subField := fmt.Sprintf("%s.Categories[%d].Text", field, i)
+		if sub == nil || sub.Text == "" {
+			v.errorf(subField, "category text is required")
+		} else if IsCategory(main) && !IsSubcategory(main, CategoryName(sub.Text)) {
+			v.errorf(subField, "unknown subcategory %q of %q", sub.Text, category.Text)
+		}
+	}
+}
+
+func (v *validator) item(field string, item *Item) {
+	if item.Title == "" {
+		v.errorf(field+".Title", "title is required")
+	}
+	if item.GUID == "" {
+		v.warnf(field+".GUID", "guid is recommended")
+	}
+	if item.PubDate == nil {
+		v.warnf(field+".PubDate", "pubDate is recommended")
+	}
+	if item.Explicit != "" {
+		v.explicit(field+".Explicit", item.Explicit)
+	}
+	switch item.EpisodeType {
+	case "", EpisodeFull, EpisodeTrailer, EpisodeBonus:
+	default:
+		v.errorf(field+".EpisodeType", "episode type %q must be %s, %s or %s", item.EpisodeType, EpisodeFull, EpisodeTrailer, EpisodeBonus)
+	}
+	if item.Season < 0 {
+		v.errorf(field+".Season", "season %d must be positive", item.Season)
+	}
+	if item.Episode < 0 {
+		v.errorf(field+".Episode", "episode %d must be positive", item.Episode)
+	}
+	if item.Image != nil {
+		v.image(field+".Image.Href", item.Image.Href)
+	}
+	v.enclosure(field+".Enclosure", item.Enclosure)
+	for i, transcript := range item.Transcripts {
+		transcriptField := fmt.Sprintf("%s.Transcripts[%d]", field, i)
+		if transcript == nil {
+			v.errorf(transcriptField, "transcript is required")
+			continue
+		}
+		v.linkedFile(transcriptField, transcript.URL, transcript.Type)
+	}
+	if item.Chapters != nil {
+		v.linkedFile(field+".Chapters", item.Chapters.URL, item.Chapters.Type)
+	}
+	if item.SimpleChapters != nil {
+		v.chapters(field+".SimpleChapters", item.SimpleChapters.Chapters, item.Duration)
+	}
+	v.cdata(field+".Description", item.Description)
+	v.cdata(field+".ContentEncoded", item.ContentEncoded)
+	v.cdata(field+".Summary", item.Summary)
+	v.extensions(field, item.Extensions, item.ExtensionAttrs)
+}
+
+// extensions reports extension elements and attributes whose prefix is not declared.
+func (v *validator) extensions(field string, extensions []*Extension, attrs []xml.Attr) {
+	for i, attr := range attrs {
+		v.extensionName(fmt.Sprintf("%s.ExtensionAttrs[%d]", field, i), attr.Name.Local)
+	}
+	v.extensionElements(field+".Extensions", extensions)
+}
+
+func (v *validator) extensionElements(field string, extensions []*Extension) {
+	for i, e := range extensions {
+		extensionField := fmt.Sprintf("%s[%d]", field, i)
+		if e == nil {
+			v.errorf(extensionField, "extension is required")
+			continue
+		}
+		v.extensionName(extensionField, e.XMLName.Local)
+		for j, attr := range e.Attrs {
+			v.extensionName(fmt.Sprintf("%s.Attrs[%d]", extensionField, j), attr.Name.Local)
+		}
+		v.extensionElements(extensionField+".Children", e.Children)
+	}
+}
+
+func (v *validator) extensionName(field, name string) {
+	if i := strings.IndexByte(name, ':'); i > 0 && !v.prefixes[name[:i]] {
+		v.errorf(field, "namespace prefix %q of %s is not declared", name[:i], name)
+	}
+}
+
+func (v *validator) cdata(field string, text *CDATAText) {
+	if text == nil {
+		return
+	}
+	if invalidXMLIndex(text.Value) >= 0 {
+		v.warnf(field, "text has characters not allowed in XML, written as U+FFFD")
+	}
+	if !strings.HasSuffix(field, ".ContentEncoded") && utf8.RuneCountInString(text.Value) > maxDescriptionLength {
+		v.warnf(field, "text is longer than %d characters", maxDescriptionLength)
+	}
+}
+
+func (v *validator) chapters(field string, chapters []*Chapter, duration *Duration) {
+	for i, chapter := range chapters {
+		chapterField := fmt.Sprintf("%s.Chapters[%d]", field, i)
+		if problem := chapterProblem(chapters, i, duration); problem != "" {
+			v.errorf(chapterField, "%s", problem)
+			continue
+		}
+		if chapter.Image != "" {
+			v.image(chapterField+".Image", chapter.Image)
+		}
+		if chapter.URL != "" {
+			v.absoluteURL(chapterField+".URL", chapter.URL)
+		}
+	}
+}
+
+func (v *validator) linkedFile(field, fileURL, mimeType string) {
+	v.absoluteURL(field+".URL", fileURL)
+	if mimeType == "" {
+		v.errorf(field+".Type", "type is required")
+	}
+}
+
+func (v *validator) enclosure(field string, enclosure *Enclosure) {
+	if enclosure == nil {
+		v.errorf(field, "enclosure is required")
+		return
+	}
+	if enclosure.URL == "" {
+		v.errorf(field+".URL", "url is required")
+	} else {
+		v.absoluteURL(field+".URL", enclosure.URL)
+	}
+	if enclosure.Type == "" {
+		v.errorf(field+".Type", "type is required")
+	}
+	if enclosure.Length == "" {
+		v.errorf(field+".Length", "length is required")
+	} else if n, err := strconv.ParseInt(enclosure.Length, 10, 64); err != nil || n <= 0 {
+		v.errorf(field+".Length", "length %q is not a positive number of bytes", enclosure.Length)
+	}
+}
+
+func (v *validator) explicit(field, value string) {
+	for _, valid := range explicitValues {
+		if value == valid {
+			return
+		}
+	}
+	v.errorf(field, "explicit %q must be one of %s", value, strings.Join(explicitValues, ", "))
+}
+
+func (v *validator) image(field, href string) {
+	if !v.absoluteURL(field, href) {
+		return
+	}
+	u, _ := url.Parse(href)
+	switch strings.ToLower(path.Ext(u.Path)) {
+	case ".jpg", ".jpeg", ".png":
+	default:
+		v.warnf(field, "artwork %q should be a JPEG or PNG file", href)
+	}
+}
+
+func (v *validator) absoluteURL(field, value string) bool {
+	u, err := url.Parse(value)
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		v.errorf(field, "invalid url %q", value)
+		return false
+	}
+	return true
+}
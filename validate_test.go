@@ -0,0 +1,272 @@
+package podcasts
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func setupValidFeed(t *testing.T) *Feed {
+	t.Helper()
+	podcast := &Podcast{
+		Title:       "Valid Podcast",
+		Description: "A podcast Apple would accept",
+		Link:        "https://example.com",
+		Language:    "en-GB",
+		Copyright:   "2024",
+	}
+	podcast.AddItem(&Item{
+		Title:   "Episode 1",
+		GUID:    "https://example.com/1",
+		PubDate: NewPubDate(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Enclosure: &Enclosure{
+			URL:    "https://example.com/1.mp3",
+			Length: "1234",
+			Type:   "audio/mpeg",
+		},
+	})
+	feed, err := podcast.Feed(
+		Author(testAuthor),
+		Explicit,
+		Owner("Owner", "owner@example.com"),
+		Image("https://example.com/artwork.jpg"),
+		SelfLink("https://example.com/feed.xml"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	feed.Channel.Categories = []*ItunesCategory{{Text: "Technology"}}
+	return feed
+}
+
+func TestValidateValidFeed(t *testing.T) {
+	feed := setupValidFeed(t)
+	if problems := feed.Validate(); problems != nil {
+		t.Errorf("unexpected problems %v", problems)
+	}
+}
+
+func TestValidateMissingChannel(t *testing.T) {
+	problems := (&Feed{}).Validate()
+	if len(problems) != 1 || problems[0].Field != "Channel" {
+		t.Errorf("unexpected problems %v", problems)
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	feed := &Feed{
+		Channel: &Channel{
+			Link:       "/relative",
+			Language:   "English",
+			Explicit:   "maybe",
+			Image:      &ItunesImage{Href: "https://example.com/artwork.gif"},
+			Owner:      &ItunesOwner{Name: "Owner", Email: "not an email"},
+			Categories: []*ItunesCategory{{Text: "Technology", Categories: []*ItunesCategory{{}}}},
+			Items: []*Item{
+				{
+					GUID:      "1",
+					Explicit:  "nope",
+					Enclosure: &Enclosure{URL: "episode.mp3", Length: "-1"},
+				},
+				{
+					Title: "Episode 2",
+					GUID:  "1",
+				},
+			},
+		},
+	}
+
+	want := map[string]Severity{
+		"Channel.Title":                            SeverityError,
+		"Channel.Description":                      SeverityError,
+		"Channel.Link":                             SeverityError,
+		"Channel.Language":                         SeverityWarning,
+		"Channel.Image.Href":                       SeverityWarning,
+		"Channel.Categories[0].Categories[0].Text": SeverityError,
+		"Channel.Explicit":                         SeverityError,
+		"Channel.Author":                           SeverityWarning,
+		"Channel.Owner.Email":                      SeverityError,
+		"Channel.AtomLinks":                        SeverityWarning,
+		"Channel.Items[0].Title":                   SeverityError,
+		"Channel.Items[0].PubDate":                 SeverityWarning,
+		"Channel.Items[0].Explicit":                SeverityError,
+		"Channel.Items[0].Enclosure.URL":           SeverityError,
+		"Channel.Items[0].Enclosure.Type":          SeverityError,
+		"Channel.Items[0].Enclosure.Length":        SeverityError,
+		"Channel.Items[1].PubDate":                 SeverityWarning,
+		"Channel.Items[1].Enclosure":               SeverityError,
+		"Channel.Items[1].GUID":                    SeverityError,
+	}
+
+	problems := feed.Validate()
+	got := make(map[string]Severity)
+	for _, problem := range problems {
+		got[problem.Field] = problem.Severity
+	}
+	for field, severity := range want {
+		if s, ok := got[field]; !ok {
+			t.Errorf("expected problem with %v", field)
+		} else if s != severity {
+			t.Errorf("expected %v to be %v got %v", field, severity, s)
+		}
+	}
+	if len(problems) != len(want) {
+		t.Errorf("expected %d problems got %d: %v", len(want), len(problems), problems)
+	}
+}
+
+func TestValidateMissingRequiredTags(t *testing.T) {
+	feed := setupValidFeed(t)
+	feed.Channel.Image = nil
+	feed.Channel.Categories = nil
+	feed.Channel.Explicit = ""
+	feed.Channel.Language = ""
+	feed.Channel.Owner = &ItunesOwner{Name: "Owner"}
+
+	problems := feed.Validate()
+	for _, field := range []string{"Channel.Image", "Channel.Categories", "Channel.Explicit", "Channel.Language"} {
+		if !hasProblem(problems, field, SeverityError) {
+			t.Errorf("expected error for %v in %v", field, problems)
+		}
+	}
+	if !hasProblem(problems, "Channel.Owner.Email", SeverityWarning) {
+		t.Errorf("expected warning for owner email in %v", problems)
+	}
+}
+
+func TestValidationErrorsErr(t *testing.T) {
+	feed := setupValidFeed(t)
+	feed.Channel.Author = ""
+	problems := feed.Validate()
+	if len(problems.Warnings()) != 1 || len(problems.Errors()) != 0 {
+		t.Fatalf("unexpected problems %v", problems)
+	}
+	if err := problems.Err(); err != nil {
+		t.Errorf("expected no error for warnings, got %v", err)
+	}
+
+	feed.Channel.Title = ""
+	err := feed.Validate().Err()
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 {
+		t.Fatalf("expected a single validation error, got %v", err)
+	}
+	want := "error: Channel.Title: title is required"
+	if err.Error() != want {
+		t.Errorf("expected %v got %v", want, err)
+	}
+}
+
+func TestSeverityString(t *testing.T) {
+	cases := map[Severity]string{
+		SeverityWarning: "warning",
+		SeverityError:   "error",
+		Severity(7):     "Severity(7)",
+	}
+	for severity, want := range cases {
+		if got := severity.String(); got != want {
+			t.Errorf("expected %v got %v", want, got)
+		}
+	}
+}
+
+func hasProblem(problems ValidationErrors, field string, severity Severity) bool {
+	for _, problem := range problems {
+		if problem.Field == field && problem.Severity == severity {
+			return true
+		}
+	}
+	return false
+}
+
+func TestValidateSerialEpisodeNumbers(t *testing.T) {
+	feed := setupValidFeed(t)
+	feed.Channel.Type = Serial
+	feed.Channel.Items = append(feed.Channel.Items,
+		&Item{Title: "Trailer", GUID: "https://example.com/trailer", EpisodeType: EpisodeTrailer},
+		&Item{Title: "Episode 2", GUID: "https://example.com/2", Episode: 2, Season: 1, EpisodeType: EpisodeFull},
+	)
+	problems := feed.Validate()
+	if !hasProblem(problems, "Channel.Items[0].Episode", SeverityError) {
+		t.Errorf("expected error for missing episode number in %v", problems)
+	}
+	if hasProblem(problems, "Channel.Items[1].Episode", SeverityError) || hasProblem(problems, "Channel.Items[2].Episode", SeverityError) {
+		t.Errorf("unexpected episode number error in %v", problems)
+	}
+
+	feed.Channel.Type = Episodic
+	if hasProblem(feed.Validate(), "Channel.Items[0].Episode", SeverityError) {
+		t.Error("episodic shows do not require episode numbers")
+	}
+}
+
+func TestValidateEpisodeTags(t *testing.T) {
+	feed := setupValidFeed(t)
+	feed.Channel.Type = "daily"
+	feed.Channel.Items[0].EpisodeType = "special"
+	feed.Channel.Items[0].Season = -1
+	feed.Channel.Items[0].Episode = -2
+
+	problems := feed.Validate()
+	for _, field := range []string{
+		"Channel.Type",
+		"Channel.Items[0].EpisodeType",
+		"Channel.Items[0].Season",
+		"Channel.Items[0].Episode",
+	} {
+		if !hasProblem(problems, field, SeverityError) {
+			t.Errorf("expected error for %v in %v", field, problems)
+		}
+	}
+}
+
+func TestValidateRSSElements(t *testing.T) {
+	feed := setupValidFeed(t)
+	feed.Channel.AtomLinks = append(feed.Channel.AtomLinks, &AtomLink{Href: "next.xml", Rel: "next"})
+	feed.Channel.TTL = -1
+	feed.Channel.SkipHours = &SkipHourList{Hours: []int{0, 24}}
+	feed.Channel.SkipDays = &SkipDayList{Days: []string{"Monday", "Mon", "Someday"}}
+
+	problems := feed.Validate()
+	for _, field := range []string{
+		"Channel.AtomLinks[1].Href",
+		"Channel.TTL",
+		"Channel.SkipHours.Hours[1]",
+		"Channel.SkipDays.Days[1]",
+		"Channel.SkipDays.Days[2]",
+	} {
+		if !hasProblem(problems, field, SeverityError) {
+			t.Errorf("expected error for %v in %v", field, problems)
+		}
+	}
+	if len(problems) != 5 {
+		t.Errorf("expected 5 problems got %v", problems)
+	}
+
+	feed.Channel.AtomLinks = nil
+	if !hasProblem(feed.Validate(), "Channel.AtomLinks", SeverityWarning) {
+		t.Error("expected warning for missing self link")
+	}
+}
+
+func TestValidateNilEntries(t *testing.T) {
+	feed := setupValidFeed(t)
+	feed.Channel.Funding = append(feed.Channel.Funding, nil)
+	feed.Channel.AtomLinks = append(feed.Channel.AtomLinks, nil)
+	feed.Channel.Items[0].Transcripts = append(feed.Channel.Items[0].Transcripts, nil)
+	want := map[string]bool{
+		"Channel.Funding[0]":              true,
+		"Channel.AtomLinks[1]":            true,
+		"Channel.Items[0].Transcripts[0]": true,
+	}
+	problems := feed.Validate()
+	for _, problem := range problems {
+		if !want[problem.Field] {
+			t.Errorf("unexpected problem %v", problem)
+		}
+		delete(want, problem.Field)
+	}
+	for field := range want {
+		t.Errorf("expected problem with %v", field)
+	}
+}